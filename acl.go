@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type ACLEvent struct {
+	Time time.Time
+	What string
+}
+
+type ACLEntry struct {
+	Admin       bool
+	Banned      bool
+	BannedUntil time.Time
+	TempBans    int
+	Strikes     int
+	LastStrike  time.Time
+	Events      []ACLEvent
+}
+
+type ACLStore struct {
+	sync.Mutex
+	filename string
+	Players  map[Player]*ACLEntry
+}
+
+var ACL *ACLStore
+
+func LoadACL(filename string) *ACLStore {
+	a := &ACLStore{filename: filename, Players: make(map[Player]*ACLEntry)}
+	if !loadJSON(filename, &a.Players) {
+		a.Players["redefiance"] = &ACLEntry{Admin: true}
+		a.Players["Great_Marcoosai"] = &ACLEntry{Banned: true}
+		a.save()
+	}
+	return a
+}
+
+func (a *ACLStore) save() {
+	saveJSON(a.filename, a.Players)
+}
+
+func (a *ACLStore) entry(player Player) *ACLEntry {
+	e := a.Players[player]
+	if e == nil {
+		e = &ACLEntry{}
+		a.Players[player] = e
+	}
+	return e
+}
+
+func (a *ACLStore) IsAdmin(player Player) bool {
+	a.Lock()
+	defer a.Unlock()
+	e := a.Players[player]
+	return e != nil && e.Admin
+}
+
+func (a *ACLStore) IsBanned(player Player) (banned bool, until time.Time) {
+	a.Lock()
+	defer a.Unlock()
+	e := a.Players[player]
+	if e == nil {
+		return false, time.Time{}
+	}
+	return e.Banned || time.Now().Before(e.BannedUntil), e.BannedUntil
+}
+
+// maxBanDoublings caps how often a ban doubles, which also keeps the duration from overflowing.
+const maxBanDoublings = 10
+
+// Strike records an offense. Every Conf.StrikesBeforeBan strikes result in a temporary ban, which
+// doubles in length with every ban the player has received before. Strikes are forgotten after
+// Conf.StrikeResetHours without a new one.
+func (a *ACLStore) Strike(player Player, reason string) (banned time.Duration) {
+	a.Lock()
+	defer a.Unlock()
+	e := a.entry(player)
+	if time.Since(e.LastStrike) > time.Duration(Conf.StrikeResetHours)*time.Hour {
+		e.Strikes = 0
+	}
+	e.Strikes++
+	e.LastStrike = time.Now()
+	e.Events = append(e.Events, ACLEvent{time.Now(), "strike: " + reason})
+
+	if e.Strikes >= Conf.StrikesBeforeBan {
+		banned = time.Duration(Conf.BanMinutes) * time.Minute << uint(min(e.TempBans, maxBanDoublings))
+		e.Strikes = 0
+		e.TempBans++
+		e.BannedUntil = time.Now().Add(banned)
+		e.Events = append(e.Events, ACLEvent{time.Now(), fmt.Sprintf("banned for %s", banned)})
+	}
+	a.save()
+	return
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStrike(t *testing.T) {
+	acl := LoadACL(filepath.Join(t.TempDir(), "acl.json"))
+	defer func(conf Config) { Conf = conf }(Conf)
+	Conf.StrikesBeforeBan, Conf.StrikeResetHours, Conf.BanMinutes = 3, 24, 15
+
+	for i := 1; i < 3; i++ {
+		if banned := acl.Strike("p", "limit"); banned != 0 {
+			t.Fatalf("strike %d banned for %s", i, banned)
+		}
+	}
+	acl.Players["p"].LastStrike = time.Now().Add(-25 * time.Hour)
+	if banned := acl.Strike("p", "limit"); banned != 0 {
+		t.Errorf("an old strike still counted, banned for %s", banned)
+	}
+	acl.Strike("p", "limit")
+	if banned := acl.Strike("p", "limit"); banned != 15*time.Minute {
+		t.Errorf("third recent strike banned for %s, want 15m", banned)
+	}
+
+	acl.Players["p"].TempBans = 40
+	acl.Players["p"].Strikes = 2
+	if banned := acl.Strike("p", "limit"); banned != 15*time.Minute<<maxBanDoublings {
+		t.Errorf("repeat offender banned for %s, want %s", banned, 15*time.Minute<<maxBanDoublings)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+type Announcement struct {
+	Kind            string
+	IntervalMinutes int
+	Template        string
+	Rooms           []Channel
+}
+
+type CardPrice struct {
+	Name  string
+	Price int
+}
+
+type announcementData struct {
+	Cards   []CardPrice
+	Current Player
+	Waiting []Player
+}
+
+var RoomActivity = make(map[Channel]time.Time)
+
+var recentlyAcquired = struct {
+	sync.Mutex
+	cards []string
+}{}
+
+var lastAnnounced = make(map[int]map[Channel]time.Time)
+
+var missingOffset int
+
+func NoteAcquired(cards []string) {
+	recentlyAcquired.Lock()
+	defer recentlyAcquired.Unlock()
+	recentlyAcquired.cards = append(recentlyAcquired.cards, cards...)
+}
+
+// RunAnnouncements posts every announcement that is due, using the configured template or the
+// catalog message in the room's language. A room counts as quiet if nobody has said
+// anything there for Conf.QuietMinutes or since the announcement was last posted.
+func (s *State) RunAnnouncements() {
+	for i, a := range Conf.Announcements {
+		if lastAnnounced[i] == nil {
+			lastAnnounced[i] = make(map[Channel]time.Time)
+		}
+
+		rooms := a.Rooms
+		if len(rooms) == 0 {
+			for _, profile := range Conf.Rooms {
+				if profile.Announce {
+					rooms = append(rooms, profile.Name)
+				}
+			}
+		}
+
+		due := make([]Channel, 0, len(rooms))
+		for _, room := range rooms {
+			last := lastAnnounced[i][room]
+			activity := RoomActivity[room]
+			if time.Since(last) < time.Duration(a.IntervalMinutes)*time.Minute ||
+				time.Since(activity) > time.Duration(Conf.QuietMinutes)*time.Minute || activity.Before(last) {
+				continue
+			}
+			due = append(due, room)
+		}
+		if len(due) == 0 {
+			continue
+		}
+
+		data, ok := s.announcementData(a.Kind)
+		if !ok {
+			continue
+		}
+
+		for _, room := range due {
+			text := ""
+			if a.Template != "" {
+				text = renderTemplate(fmt.Sprintf("announcement/%d", i), a.Template, data)
+			} else {
+				lang := "en"
+				if profile, ok := RoomProfileFor(room); ok && profile.Language != "" {
+					lang = profile.Language
+				}
+				text = Tr(lang, "announce."+a.Kind, data)
+			}
+			if text != "" {
+				s.Say(room, text)
+			}
+			lastAnnounced[i][room] = time.Now()
+		}
+	}
+}
+
+func (s *State) announcementData(kind string) (data announcementData, ok bool) {
+	const maxCards = 5
+
+	byPrice := func(cards []CardPrice) []CardPrice {
+		sort.Slice(cards, func(i, j int) bool {
+			if cards[i].Price != cards[j].Price {
+				return cards[i].Price > cards[j].Price
+			}
+			return cards[i].Name < cards[j].Name
+		})
+		if len(cards) > maxCards {
+			cards = cards[:maxCards]
+		}
+		return cards
+	}
+
+	switch kind {
+	case "demand":
+		for _, card := range CardTypes {
+			if price := s.DeterminePrice(card, 1, true); price <= GoldForTrade() {
+				data.Cards = append(data.Cards, CardPrice{card, price})
+			}
+		}
+		data.Cards = byPrice(data.Cards)
+
+	case "acquired":
+		recentlyAcquired.Lock()
+		for _, card := range recentlyAcquired.cards {
+			data.Cards = append(data.Cards, CardPrice{card, s.DeterminePrice(card, 1, false)})
+		}
+		recentlyAcquired.cards = nil
+		recentlyAcquired.Unlock()
+
+	case "missing":
+		for _, card := range CardTypes {
+			if Stocks[Bot][card] == 0 {
+				data.Cards = append(data.Cards, CardPrice{card, s.DeterminePrice(card, 1, true)})
+			}
+		}
+		sort.Slice(data.Cards, func(i, j int) bool { return data.Cards[i].Name < data.Cards[j].Name })
+		if len(data.Cards) > maxCards {
+			missingOffset = (missingOffset + maxCards) % len(data.Cards)
+			data.Cards = append(data.Cards[missingOffset:], data.Cards[:missingOffset]...)[:maxCards]
+		}
+
+	case "queue":
+		data.Current = Queue.Current()
+		data.Waiting = Queue.Waiting()
+		return data, true
+
+	case "hint":
+		return data, true
+
+	case "auction":
+		for _, auction := range Auctions.Running() {
+			data.Cards = append(data.Cards, CardPrice{auction.Card, auction.MinimumBid()})
+		}
+
+	default:
+		log.Printf("unknown announcement kind '%s'", kind)
+		return data, false
+	}
+	return data, len(data.Cards) > 0
+}
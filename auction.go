@@ -0,0 +1,283 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Bid struct {
+	Player Player
+	Amount int
+	Time   time.Time
+}
+
+type Auction struct {
+	Id      int
+	Card    string
+	Reserve int
+	Ends    time.Time
+	Bids    []Bid
+
+	Closed     bool
+	Winner     Player
+	Price      int
+	ClaimUntil time.Time
+	Paid       bool
+}
+
+type AuctionStore struct {
+	sync.Mutex
+	filename string
+	NextId   int
+	Auctions []*Auction
+}
+
+var Auctions *AuctionStore
+
+func LoadAuctions(filename string) *AuctionStore {
+	a := &AuctionStore{filename: filename, NextId: 1}
+	loadJSON(filename, a)
+	return a
+}
+
+func (a *AuctionStore) save() {
+	saveJSON(a.filename, a)
+}
+
+// auctionHolder is who auctioned cards are reserved for until the auction closes.
+func auctionHolder(auction *Auction) Player {
+	return Player(fmt.Sprintf("#auction%d", auction.Id))
+}
+
+func (auction *Auction) HighestBid() (bid Bid, ok bool) {
+	if len(auction.Bids) == 0 {
+		return bid, false
+	}
+	return auction.Bids[len(auction.Bids)-1], true
+}
+
+// MinimumBid is the reserve price for the first bid and the highest bid plus Conf.AuctionIncrement percent
+// (at least 1g) after that.
+func (auction *Auction) MinimumBid() int {
+	bid, ok := auction.HighestBid()
+	if !ok {
+		return auction.Reserve
+	}
+	increment := bid.Amount * Conf.AuctionIncrement / 100
+	if increment < 1 {
+		increment = 1
+	}
+	return bid.Amount + increment
+}
+
+func (a *AuctionStore) Open(card string, reserve int, d time.Duration) (*Auction, bool) {
+	a.Lock()
+	defer a.Unlock()
+	auction := &Auction{Id: a.NextId, Card: card, Reserve: reserve, Ends: time.Now().Add(d)}
+	if held := Reservations.Hold(auctionHolder(auction), map[string]int{card: 1}, d); held[card] == 0 {
+		return nil, false
+	}
+	a.NextId++
+	a.Auctions = append(a.Auctions, auction)
+	a.save()
+	return auction, true
+}
+
+func (a *AuctionStore) Running() []Auction {
+	a.Lock()
+	defer a.Unlock()
+	running := make([]Auction, 0)
+	for _, auction := range a.Auctions {
+		if !auction.Closed {
+			running = append(running, *auction)
+		}
+	}
+	return running
+}
+
+// find returns the running auction for the card, or the only running one if card is empty.
+func (a *AuctionStore) find(card string) *Auction {
+	var found *Auction
+	for _, auction := range a.Auctions {
+		if auction.Closed || (card != "" && auction.Card != card) {
+			continue
+		}
+		if found != nil {
+			return nil
+		}
+		found = auction
+	}
+	return found
+}
+
+// Bid places a bid and returns the player it outbid, if any.
+func (a *AuctionStore) Bid(player Player, card string, amount int) (auction Auction, outbid Player, err string) {
+	a.Lock()
+	defer a.Unlock()
+	found := a.find(card)
+	if found == nil || time.Now().After(found.Ends) {
+		return auction, "", "auction.which"
+	}
+	if amount < found.MinimumBid() {
+		return *found, "", "auction.too_low"
+	}
+	if bid, ok := found.HighestBid(); ok && bid.Player != player {
+		outbid = bid.Player
+	}
+	found.Bids = append(found.Bids, Bid{player, amount, time.Now()})
+	a.save()
+	return *found, outbid, ""
+}
+
+// WonBy returns what the player still has to pay for each copy of the card they won at auction.
+func (a *AuctionStore) WonBy(player Player, card string) []int {
+	a.Lock()
+	defer a.Unlock()
+	prices := make([]int, 0)
+	for _, auction := range a.Auctions {
+		if auction.Closed && !auction.Paid && auction.Winner == player && auction.Card == card && time.Now().Before(auction.ClaimUntil) {
+			prices = append(prices, auction.Price)
+		}
+	}
+	return prices
+}
+
+// Settle marks one of the player's won auctions as paid for each copy of its card they just traded.
+func (a *AuctionStore) Settle(player Player, cards map[string]int) {
+	a.Lock()
+	defer a.Unlock()
+	left := make(map[string]int, len(cards))
+	for card, num := range cards {
+		left[card] = num
+	}
+	for _, auction := range a.Auctions {
+		if auction.Closed && !auction.Paid && auction.Winner == player && left[auction.Card] > 0 {
+			auction.Paid = true
+			left[auction.Card]--
+		}
+	}
+	a.save()
+}
+
+// SellPrice is what we charge the player for num copies of the card, taking won auctions and raffles
+// into account.
+func (s *State) SellPrice(player Player, card string, num int) int {
+	if prize := Raffles.PrizeFor(player)[card]; prize > 0 {
+		if num <= prize {
+			return 0
+		}
+		num -= prize
+	}
+	price := 0
+	won := Auctions.WonBy(player, card)
+	for len(won) > 0 && num > 0 {
+		price += won[0]
+		won, num = won[1:], num-1
+	}
+	if num > 0 {
+		price += s.DeterminePrice(card, num, false)
+	}
+	return price
+}
+
+// parseBid reads "[card] <amount>".
+func parseBid(str string) (card string, amount int, options []string) {
+	words := strings.Fields(str)
+	if len(words) == 0 {
+		return "", 0, nil
+	}
+	amount, err := strconv.Atoi(strings.TrimSuffix(words[len(words)-1], "g"))
+	if err != nil {
+		return "", 0, nil
+	}
+	if len(words) > 1 {
+		card, options = resolveCardName(strings.Join(words[:len(words)-1], " "))
+	}
+	return card, amount, options
+}
+
+// holdWinnings reserves won but unpaid cards for their winners again. Holds aren't saved, so they're
+// lost when the bot restarts while the auctions are not.
+func (a *AuctionStore) holdWinnings() {
+	won := make(map[Player]map[string]int)
+	until := make(map[Player]time.Time)
+	for _, auction := range a.Auctions {
+		if !auction.Closed || auction.Winner == "" || auction.Paid || time.Now().After(auction.ClaimUntil) {
+			continue
+		}
+		if won[auction.Winner] == nil {
+			won[auction.Winner] = make(map[string]int)
+		}
+		won[auction.Winner][auction.Card]++
+		if auction.ClaimUntil.After(until[auction.Winner]) {
+			until[auction.Winner] = auction.ClaimUntil
+		}
+	}
+	for player, cards := range won {
+		held := Reservations.HeldCards(player)
+		for card, num := range cards {
+			if held[card] < num {
+				Reservations.Hold(player, cards, until[player].Sub(time.Now()))
+				break
+			}
+		}
+	}
+}
+
+// RunAuctions keeps the cards of running auctions and unpaid wins reserved and closes the auctions
+// that ended. Winners get the card reserved for them at their bid and are put in the trade queue.
+func (s *State) RunAuctions() {
+	Auctions.Lock()
+	Auctions.holdWinnings()
+	closed := make([]Auction, 0)
+	for _, auction := range Auctions.Auctions {
+		if auction.Closed {
+			continue
+		}
+		if time.Now().Before(auction.Ends) {
+			Reservations.Hold(auctionHolder(auction), map[string]int{auction.Card: 1}, auction.Ends.Sub(time.Now()))
+			continue
+		}
+		auction.Closed = true
+		Reservations.Release(auctionHolder(auction))
+		if bid, ok := auction.HighestBid(); ok {
+			auction.Winner = bid.Player
+			auction.Price = bid.Amount
+			auction.ClaimUntil = time.Now().Add(time.Duration(Conf.AuctionClaimMinutes) * time.Minute)
+			held := Reservations.HeldCards(bid.Player)
+			Reservations.Hold(bid.Player, map[string]int{auction.Card: held[auction.Card] + 1}, time.Duration(Conf.AuctionClaimMinutes)*time.Minute)
+		}
+		closed = append(closed, *auction)
+	}
+	kept := Auctions.Auctions[:0]
+	for _, auction := range Auctions.Auctions {
+		if !auction.Closed || (auction.Winner != "" && !auction.Paid && time.Now().Before(auction.ClaimUntil)) {
+			kept = append(kept, auction)
+		}
+	}
+	if len(closed) > 0 || len(kept) != len(Auctions.Auctions) {
+		Auctions.Auctions = kept
+		Auctions.save()
+	}
+	Auctions.Unlock()
+
+	for _, auction := range closed {
+		if auction.Winner == "" {
+			s.Announce("auction.unsold", auction)
+			continue
+		}
+		s.Announce("auction.won", auction)
+		AddWTBRequest(auction.Winner, auction.Card, 1)
+		position, added := Queue.Add(auction.Winner)
+		s.WhisperTr(auction.Winner, "auction.you_won", Vars{
+			"Card":     auction.Card,
+			"Price":    auction.Price,
+			"Position": position,
+			"Trading":  position == 0 && !added,
+			"Minutes":  Conf.AuctionClaimMinutes,
+		})
+	}
+}
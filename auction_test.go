@@ -0,0 +1,23 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAuctionSettle(t *testing.T) {
+	until := time.Now().Add(time.Hour)
+	a := &AuctionStore{filename: filepath.Join(t.TempDir(), "auctions.json")}
+	for _, price := range []int{100, 200, 300} {
+		a.Auctions = append(a.Auctions, &Auction{Card: "Wolf", Closed: true, Winner: "alice", Price: price, ClaimUntil: until})
+	}
+
+	if won := a.WonBy("alice", "Wolf"); len(won) != 3 {
+		t.Fatalf("WonBy = %v, want three prices", won)
+	}
+	a.Settle("alice", map[string]int{"Wolf": 2})
+	if won := a.WonBy("alice", "Wolf"); len(won) != 1 || won[0] != 300 {
+		t.Errorf("after trading two wolves WonBy = %v, want only the third auction unpaid", won)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var (
+	benchmarkAdjectives = []string{
+		"Ancient", "Blazing", "Crimson", "Dark", "Elder", "Frozen", "Gilded", "Hollow", "Iron", "Jade", "Lunar",
+		"Mighty", "Night", "Obsidian", "Pale", "Raging", "Silent", "Thunder", "Undying", "Vengeful", "Wild", "Young",
+	}
+	benchmarkNouns = []string{
+		"Archer", "Behemoth", "Cleric", "Drake", "Enchantress", "Falcon", "Golem", "Harbinger", "Imp", "Juggernaut",
+		"Knight", "Lancer", "Mystic", "Nomad", "Oracle", "Paladin", "Raven", "Sentinel", "Titan", "Valkyrie",
+		"Warden", "Wyrm", "Zealot", "Shaman", "Brute",
+	}
+)
+
+// useBenchmarkCards loads a synthetic card pool the size of a large live catalog, 22 adjectives times
+// 25 nouns.
+func useBenchmarkCards(b *testing.B) {
+	names := make([]string, 0, len(benchmarkAdjectives)*len(benchmarkNouns))
+	for _, adjective := range benchmarkAdjectives {
+		for _, noun := range benchmarkNouns {
+			names = append(names, adjective+" "+noun)
+		}
+	}
+	useCardTypes(b, names...)
+}
+
+// benchmarkList builds a bulk WTB list from n card names, with every other name misspelled by
+// swapping two letters.
+func benchmarkList(n int) []string {
+	names := Index().names
+	if len(names) > n {
+		names = names[:n]
+	}
+	words := make([]string, len(names))
+	for i, name := range names {
+		word := strings.ToLower(name)
+		if i%2 == 1 {
+			b := []byte(word)
+			b[1], b[2] = b[2], b[1]
+			word = string(b)
+		}
+		words[i] = fmt.Sprintf("%dx %s", i%3+1, word)
+	}
+	return words
+}
+
+func BenchmarkResolveCard(b *testing.B) {
+	useBenchmarkCards(b)
+	list := benchmarkList(50)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ResolveCard(list[i%len(list)][3:])
+	}
+}
+
+func BenchmarkParseCardList(b *testing.B) {
+	useBenchmarkCards(b)
+	list := strings.Join(benchmarkList(50), ", ")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		parseCardList(list, nil)
+	}
+}
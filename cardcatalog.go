@@ -0,0 +1,268 @@
+package main
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var rarityNames = []string{"common", "uncommon", "rare"}
+
+var kindWords = map[string]string{
+	"creature": "creature", "creatures": "creature",
+	"structure": "structure", "structures": "structure",
+	"spell": "spell", "spells": "spell",
+	"enchantment": "enchantment", "enchantments": "enchantment",
+}
+
+type CardAbility struct {
+	Name        string
+	Description string
+	Cost        int
+}
+
+type Card struct {
+	Id          CardId
+	Name        string
+	Description string
+	Flavor      string
+	Kind        string
+	SubTypes    string
+	Rarity      int
+	Resource    string
+	Cost        int
+	Ap          int
+	Ac          int
+	Hp          int
+	Set         int
+	Available   bool
+	Rules       []string
+	Passives    []string
+	Abilities   []CardAbility
+}
+
+func (c *Card) RarityName() string {
+	if c.Rarity >= 0 && c.Rarity < len(rarityNames) {
+		return rarityNames[c.Rarity]
+	}
+	return "unknown"
+}
+
+// Attribute returns the value of a groupable attribute: rarity, resource, kind, set or cost.
+func (c *Card) Attribute(name string) string {
+	switch name {
+	case "rarity":
+		return c.RarityName()
+	case "resource":
+		return c.Resource
+	case "kind":
+		return c.Kind
+	case "set":
+		return strconv.Itoa(c.Set)
+	case "cost":
+		return strconv.Itoa(c.Cost)
+	}
+	return ""
+}
+
+type CardCatalog struct {
+	sync.RWMutex
+	byId   map[CardId]*Card
+	byName map[string]*Card
+}
+
+var Cards = &CardCatalog{
+	byId:   make(map[CardId]*Card),
+	byName: make(map[string]*Card),
+}
+
+func (cc *CardCatalog) Load(v MCardTypes) {
+	byId := make(map[CardId]*Card)
+	byName := make(map[string]*Card)
+
+	for _, t := range v.CardTypes {
+		card := &Card{
+			Id:          CardId(t.Id),
+			Name:        t.Name,
+			Description: t.Description,
+			Flavor:      t.Flavor,
+			Kind:        strings.ToLower(t.Kind),
+			SubTypes:    t.SubTypesStr,
+			Rarity:      t.Rarity,
+			Ap:          t.Ap,
+			Ac:          t.Ac,
+			Hp:          t.Hp,
+			Set:         t.Set,
+			Available:   t.Available,
+			Rules:       t.RulesList,
+		}
+		switch {
+		case t.CostGrowth > 0:
+			card.Resource, card.Cost = "growth", t.CostGrowth
+		case t.CostOrder > 0:
+			card.Resource, card.Cost = "order", t.CostOrder
+		case t.CostEnergy > 0:
+			card.Resource, card.Cost = "energy", t.CostEnergy
+		case t.CostDecay > 0:
+			card.Resource, card.Cost = "decay", t.CostDecay
+		}
+		for _, rule := range t.PassiveRules {
+			card.Passives = append(card.Passives, rule.DisplayName)
+		}
+		for _, ability := range t.Abilities {
+			cost := ability.Cost.Growth + ability.Cost.Order + ability.Cost.Energy + ability.Cost.Decay
+			card.Abilities = append(card.Abilities, CardAbility{ability.Name, ability.Description, cost})
+		}
+		byId[card.Id] = card
+		byName[card.Name] = card
+	}
+
+	cc.Lock()
+	cc.byId, cc.byName = byId, byName
+	cc.Unlock()
+}
+
+func (cc *CardCatalog) ById(id CardId) (card *Card, ok bool) {
+	cc.RLock()
+	defer cc.RUnlock()
+	card, ok = cc.byId[id]
+	return
+}
+
+func (cc *CardCatalog) ByName(name string) (card *Card, ok bool) {
+	cc.RLock()
+	defer cc.RUnlock()
+	card, ok = cc.byName[name]
+	return
+}
+
+// Filter returns all cards matching f, ordered by name.
+func (cc *CardCatalog) Filter(f CardFilter) []*Card {
+	cc.RLock()
+	defer cc.RUnlock()
+
+	cards := make([]*Card, 0)
+	for _, card := range cc.byId {
+		if f.Matches(card) {
+			cards = append(cards, card)
+		}
+	}
+	sort.Slice(cards, func(i, j int) bool { return cards[i].Name < cards[j].Name })
+	return cards
+}
+
+// GroupBy groups the cards by one of the attributes understood by Card.Attribute.
+func GroupBy(cards []*Card, attribute string) map[string][]*Card {
+	groups := make(map[string][]*Card)
+	for _, card := range cards {
+		key := card.Attribute(attribute)
+		groups[key] = append(groups[key], card)
+	}
+	return groups
+}
+
+type CardFilter struct {
+	Rarity   int
+	Resource string
+	Kind     string
+	Set      int
+	MinCost  int
+	MaxCost  int
+	Name     string
+}
+
+func NewCardFilter() CardFilter {
+	return CardFilter{Rarity: -1, MinCost: -1, MaxCost: -1}
+}
+
+func (f CardFilter) Matches(card *Card) bool {
+	return (f.Rarity < 0 || card.Rarity == f.Rarity) &&
+		(f.Resource == "" || card.Resource == f.Resource) &&
+		(f.Kind == "" || card.Kind == f.Kind) &&
+		(f.Set == 0 || card.Set == f.Set) &&
+		(f.MinCost < 0 || card.Cost >= f.MinCost) &&
+		(f.MaxCost < 0 || card.Cost <= f.MaxCost) &&
+		(f.Name == "" || strings.Contains(strings.ToLower(card.Name), f.Name))
+}
+
+func (f CardFilter) IsEmpty() bool {
+	return f == NewCardFilter()
+}
+
+// applyWord narrows the filter by a bare rarity, resource or kind word like "rares" or "decay".
+func (f *CardFilter) applyWord(word string) bool {
+	if rarity, ok := rarityWords[word]; ok && f.Rarity < 0 {
+		f.Rarity = rarity
+	} else if resource, ok := resourceWords[word]; ok && f.Resource == "" {
+		f.Resource = resource
+	} else if kind, ok := kindWords[word]; ok && f.Kind == "" {
+		f.Kind = kind
+	} else {
+		return false
+	}
+	return true
+}
+
+func parseCostRange(value string) (min, max int, err error) {
+	switch {
+	case strings.HasSuffix(value, "+"):
+		min, err = strconv.Atoi(strings.TrimSuffix(value, "+"))
+		return min, -1, err
+	case strings.Contains(value, "-"):
+		split := strings.SplitN(value, "-", 2)
+		if min, err = strconv.Atoi(split[0]); err == nil {
+			max, err = strconv.Atoi(split[1])
+		}
+		return
+	}
+	min, err = strconv.Atoi(value)
+	return min, min, err
+}
+
+// ParseCardFilter understands filters like "decay creature cost:2-4 set:1 rarity:rare". Words that
+// aren't a rarity, resource or kind are matched against card names.
+func ParseCardFilter(str string) (f CardFilter, err error) {
+	f = NewCardFilter()
+	name := make([]string, 0)
+
+	for _, word := range strings.Fields(strings.ToLower(str)) {
+		split := strings.SplitN(word, ":", 2)
+		if len(split) == 1 {
+			if !f.applyWord(word) {
+				name = append(name, word)
+			}
+			continue
+		}
+
+		key, value := split[0], split[1]
+		switch key {
+		case "rarity", "resource", "faction", "kind":
+			valid := false
+			switch key {
+			case "rarity":
+				_, valid = rarityWords[value]
+			case "kind":
+				_, valid = kindWords[value]
+			default:
+				_, valid = resourceWords[value]
+			}
+			if !valid || !f.applyWord(value) {
+				return f, errors.New(word)
+			}
+		case "set":
+			if f.Set, err = strconv.Atoi(value); err != nil {
+				return f, errors.New(word)
+			}
+		case "cost":
+			if f.MinCost, f.MaxCost, err = parseCostRange(value); err != nil {
+				return f, errors.New(word)
+			}
+		default:
+			return f, errors.New(word)
+		}
+	}
+	f.Name = strings.Join(name, " ")
+	return f, nil
+}
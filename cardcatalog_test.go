@@ -0,0 +1,46 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCardFilter(t *testing.T) {
+	for _, test := range []struct {
+		input string
+		want  func(f *CardFilter)
+		err   string
+	}{
+		{"decay creature", func(f *CardFilter) { f.Resource, f.Kind = "decay", "creature" }, ""},
+		{"rarity:rare faction:order kind:spells", func(f *CardFilter) { f.Rarity, f.Resource, f.Kind = 2, "order", "spell" }, ""},
+		{"cost:2-4 set:1", func(f *CardFilter) { f.MinCost, f.MaxCost, f.Set = 2, 4, 1 }, ""},
+		{"cost:5+ wolf rider", func(f *CardFilter) { f.MinCost, f.Name = 5, "wolf rider" }, ""},
+		{"rarity:decay", nil, "rarity:decay"},
+		{"kind:rare", nil, "kind:rare"},
+		{"resource:creature", nil, "resource:creature"},
+		{"rare rarity:common", nil, "rarity:common"},
+		{"cost:x", nil, "cost:x"},
+		{"color:red", nil, "color:red"},
+	} {
+		f, err := ParseCardFilter(test.input)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("ParseCardFilter(%q) error = %v, want %s", test.input, err, test.err)
+			}
+			continue
+		}
+		want := NewCardFilter()
+		test.want(&want)
+		if err != nil || f != want {
+			t.Errorf("ParseCardFilter(%q) = %+v, %v, want %+v", test.input, f, err, want)
+		}
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	groups := GroupBy([]*Card{wolf, dragon, bolt, wolf}, "rarity")
+	want := map[string][]*Card{"common": {wolf, wolf}, "rare": {dragon, bolt}}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("GroupBy(rarity) = %v, want %v", groups, want)
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CardIndex answers fuzzy card name lookups without running an edit distance against every card type.
+// It's rebuilt whenever CardTypes arrives.
+type CardIndex struct {
+	names    []string
+	lower    []string
+	exact    map[string]string
+	words    map[string][]string
+	trigrams map[string][]int
+}
+
+var cardIndex = struct {
+	sync.RWMutex
+	idx *CardIndex
+}{idx: &CardIndex{}}
+
+func trigrams(word string) []string {
+	padded := "  " + word + "  "
+	grams := make([]string, 0, len(word)+2)
+	for i := 0; i+3 <= len(padded); i++ {
+		grams = append(grams, padded[i:i+3])
+	}
+	return grams
+}
+
+func RebuildCardIndex() {
+	idx := &CardIndex{
+		exact:    make(map[string]string),
+		words:    make(map[string][]string),
+		trigrams: make(map[string][]int),
+	}
+	for _, name := range CardTypes {
+		idx.names = append(idx.names, name)
+	}
+	sort.Strings(idx.names)
+
+	for i, name := range idx.names {
+		lower := strings.ToLower(name)
+		idx.lower = append(idx.lower, lower)
+		idx.exact[lower] = name
+		for _, word := range strings.Split(lower, " ") {
+			idx.words[word] = append(idx.words[word], name)
+		}
+		for _, gram := range trigrams(lower) {
+			idx.trigrams[gram] = append(idx.trigrams[gram], i)
+		}
+	}
+
+	cardIndex.Lock()
+	cardIndex.idx = idx
+	cardIndex.Unlock()
+}
+
+func Index() *CardIndex {
+	cardIndex.RLock()
+	defer cardIndex.RUnlock()
+	return cardIndex.idx
+}
+
+func (idx *CardIndex) Exact(word string) (name string, ok bool) {
+	name, ok = idx.exact[word]
+	return
+}
+
+// Fuzzy calls found for every card name within radius edits of the lowercase word. A single edit
+// touches at most three trigrams and a transposition at most four, so names sharing too few trigrams
+// with the word are skipped without computing the distance.
+func (idx *CardIndex) Fuzzy(word string, radius int, found func(name string, dist int)) {
+	shared := make([]int, len(idx.names))
+	for _, gram := range trigrams(word) {
+		for _, i := range idx.trigrams[gram] {
+			shared[i]++
+		}
+	}
+
+	for i, lower := range idx.lower {
+		if len(lower) > len(word)+radius || len(word) > len(lower)+radius {
+			continue
+		}
+		if shared[i] < max(len(lower), len(word))+2-4*radius {
+			continue
+		}
+		if dist := DamerauLevenshtein(word, lower); dist <= radius {
+			found(idx.names[i], dist)
+		}
+	}
+}
+
+func (idx *CardIndex) WithWord(word string) []string {
+	return idx.words[word]
+}
+
+// Containing calls found for every card name that contains the lowercase substring. Only names sharing
+// the substring's rarest trigram are checked.
+func (idx *CardIndex) Containing(substr string, found func(name string, length int)) {
+	if len(substr) < 3 {
+		for i, lower := range idx.lower {
+			if strings.Contains(lower, substr) {
+				found(idx.names[i], len(lower))
+			}
+		}
+		return
+	}
+
+	var rarest []int
+	for i := 0; i+3 <= len(substr); i++ {
+		postings := idx.trigrams[substr[i:i+3]]
+		if i == 0 || len(postings) < len(rarest) {
+			rarest = postings
+		}
+	}
+	last := -1
+	for _, i := range rarest {
+		if i != last && strings.Contains(idx.lower[i], substr) {
+			found(idx.names[i], len(idx.lower[i]))
+		}
+		last = i
+	}
+}
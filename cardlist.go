@@ -0,0 +1,227 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var reQuantity = regexp.MustCompile(`^(?:x(\d+)|(\d+)x?)$`)
+var reInvalidChars = regexp.MustCompile("[^a-z'0-9]")
+
+var rarityWords = map[string]int{
+	"common": 0, "commons": 0,
+	"uncommon": 1, "uncommons": 1,
+	"rare": 2, "rares": 2,
+}
+
+var resourceWords = map[string]string{
+	"growth": "growth",
+	"order":  "order",
+	"energy": "energy",
+	"decay":  "decay",
+}
+
+type ParseError struct {
+	Pos       int
+	Word      string
+	Options   []string
+	NoLibrary bool // "all" or "every" without a known collection to count
+}
+
+type token struct {
+	text string
+	pos  int
+}
+
+const (
+	quantityAll   = -1
+	quantityEvery = -2
+)
+
+// tokenizeCardList splits the list into items at commas, semicolons, newlines and the word "and",
+// and each item into words. Positions count from 1.
+func tokenizeCardList(str string) [][]token {
+	items := make([][]token, 0)
+	item := make([]token, 0)
+	word := -1
+
+	endWord := func(end int) {
+		if word >= 0 {
+			text := reInvalidChars.ReplaceAllString(strings.ToLower(str[word:end]), "")
+			if text != "" {
+				item = append(item, token{text, word + 1})
+			}
+		}
+		word = -1
+	}
+	endItem := func() {
+		items = append(items, splitAtAnd(item)...)
+		item = make([]token, 0)
+	}
+
+	for i, c := range str {
+		switch c {
+		case ',', ';', '\n', '\r':
+			endWord(i)
+			endItem()
+		case ' ', '\t':
+			endWord(i)
+		default:
+			if word < 0 {
+				word = i
+			}
+		}
+	}
+	endWord(len(str))
+	endItem()
+	return items
+}
+
+// splitAtAnd splits an item at every "and", unless the whole item is the name of a card, with or
+// without a leading or trailing quantity.
+func splitAtAnd(item []token) [][]token {
+	name := item
+	if len(name) > 1 {
+		if _, ok := parseQuantity(name[0].text); ok {
+			name = name[1:]
+		} else if _, ok := parseQuantity(name[len(name)-1].text); ok {
+			name = name[:len(name)-1]
+		}
+	}
+	if _, ok := Index().Exact(joinTokens(name)); ok {
+		return [][]token{item}
+	}
+	items := make([][]token, 0, 1)
+	start := 0
+	for i, t := range item {
+		if t.text == "and" {
+			items = append(items, item[start:i])
+			start = i + 1
+		}
+	}
+	return append(items, item[start:])
+}
+
+func joinTokens(tokens []token) string {
+	words := make([]string, len(tokens))
+	for i, t := range tokens {
+		words[i] = t.text
+	}
+	return strings.Join(words, " ")
+}
+
+func parseQuantity(text string) (num int, ok bool) {
+	switch text {
+	case "all":
+		return quantityAll, true
+	case "every", "each":
+		return quantityEvery, true
+	}
+	match := reQuantity.FindStringSubmatch(text)
+	if match == nil {
+		return 0, false
+	}
+	num, _ = strconv.Atoi(match[1] + match[2])
+	return num, num > 0
+}
+
+// parseWildcard matches selectors like "commons" or "decay rare creatures" against all card types.
+func parseWildcard(tokens []token) (cards []string, ok bool) {
+	f := NewCardFilter()
+	for _, t := range tokens {
+		if !f.applyWord(t.text) && t.text != "cards" && t.text != "scrolls" {
+			return nil, false
+		}
+	}
+	if f.IsEmpty() {
+		return nil, false
+	}
+
+	for _, card := range Cards.Filter(f) {
+		cards = append(cards, card.Name)
+	}
+	return cards, true
+}
+
+// parseCardList understands lists like "3x gravelock elder; ilmire hunter x2 and all decay rares".
+// Quantities may lead or trail a card name. "all" takes every copy listed in available, "every" one
+// copy of each available card type. If available is nil, both are reported as errors.
+func parseCardList(str string, available map[string]int) (cards map[string]int, errors []ParseError) {
+	cards = make(map[string]int)
+
+	for _, item := range tokenizeCardList(str) {
+		if len(item) == 0 {
+			continue
+		}
+
+		name := joinTokens(item)
+		card, options := resolveCardName(name)
+		num := 1
+		if _, exact := Index().Exact(name); !exact && len(item) > 1 {
+			rest := item
+			hasQuantity := false
+			if quantity, ok := parseQuantity(rest[0].text); ok {
+				num, rest, hasQuantity = quantity, rest[1:], true
+			} else if quantity, ok := parseQuantity(rest[len(rest)-1].text); ok && quantity > 0 {
+				num, rest, hasQuantity = quantity, rest[:len(rest)-1], true
+			}
+
+			if hasQuantity && num < 0 && available == nil {
+				errors = append(errors, ParseError{Pos: item[0].pos, Word: name, NoLibrary: true})
+				continue
+			}
+			if hasQuantity {
+				if wildcard, ok := parseWildcard(rest); ok {
+					for _, card := range wildcard {
+						if n := countFor(card, num, available); n > 0 {
+							cards[card] += n
+						}
+					}
+					continue
+				}
+				card, options = resolveCardName(joinTokens(rest))
+			}
+		}
+
+		if card != "" {
+			if n := countFor(card, num, available); n > 0 {
+				cards[card] += n
+			}
+		} else {
+			errors = append(errors, ParseError{Pos: item[0].pos, Word: name, Options: options})
+		}
+	}
+	return
+}
+
+func countFor(card string, num int, available map[string]int) int {
+	switch {
+	case num == quantityEvery:
+		return min(available[card], 1)
+	case num == quantityAll:
+		return available[card]
+	}
+	return num
+}
+
+// parseProblems explains which items of a card list couldn't be resolved.
+func parseProblems(lang string, errors []ParseError) string {
+	unknown := make([]ParseError, 0, len(errors))
+	problems := make([]string, 0, len(errors))
+	for _, e := range errors {
+		if e.NoLibrary {
+			problems = append(problems, Tr(lang, "parse.no_library", e))
+		} else if len(e.Options) > 0 {
+			problems = append(problems, Tr(lang, "card.ambiguous", e))
+		} else {
+			unknown = append(unknown, e)
+		}
+	}
+	sort.SliceStable(unknown, func(i, j int) bool { return unknown[i].Pos < unknown[j].Pos })
+	if len(unknown) > 0 {
+		problems = append([]string{Tr(lang, "parse.unknown", Vars{"Errors": unknown})}, problems...)
+	}
+	return strings.Join(problems, " ")
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenizeCardList(t *testing.T) {
+	useCardTypes(t, "Fire and Ice", "Wolf", "Bear")
+
+	for _, test := range []struct {
+		input string
+		want  [][]token
+	}{
+		{"wolf", [][]token{{{"wolf", 1}}}},
+		{"3x Wolf, bear", [][]token{{{"3x", 1}, {"wolf", 4}}, {{"bear", 10}}}},
+		{"wolf;bear\nwolf", [][]token{{{"wolf", 1}}, {{"bear", 6}}, {{"wolf", 11}}}},
+		{"wolf and  bear", [][]token{{{"wolf", 1}}, {{"bear", 11}}}},
+		{"fire and ice", [][]token{{{"fire", 1}, {"and", 6}, {"ice", 10}}}},
+		{"Wolf's!", [][]token{{{"wolf's", 1}}}},
+		{"wolf,,", [][]token{{{"wolf", 1}}, {}, {}}},
+		{"", [][]token{{}}},
+	} {
+		if got := tokenizeCardList(test.input); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("tokenizeCardList(%q) = %v, want %v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestParseQuantity(t *testing.T) {
+	for _, test := range []struct {
+		text string
+		num  int
+		ok   bool
+	}{
+		{"3", 3, true},
+		{"3x", 3, true},
+		{"x12", 12, true},
+		{"all", quantityAll, true},
+		{"every", quantityEvery, true},
+		{"each", quantityEvery, true},
+		{"0", 0, false},
+		{"x", 0, false},
+		{"3x3", 0, false},
+		{"wolf", 0, false},
+	} {
+		if num, ok := parseQuantity(test.text); num != test.num || ok != test.ok {
+			t.Errorf("parseQuantity(%q) = %d, %v, want %d, %v", test.text, num, ok, test.num, test.ok)
+		}
+	}
+}
+
+func TestParseCardList(t *testing.T) {
+	useCardTypes(t, "Fire and Ice", "Wolf", "Wolf Rider", "Bear", "Bear Trap")
+	available := map[string]int{"Wolf": 4, "Bear": 2}
+
+	for _, test := range []struct {
+		input     string
+		available map[string]int
+		cards     map[string]int
+		errors    []ParseError
+	}{
+		{"3x wolf, bear x2", nil, map[string]int{"Wolf": 3, "Bear": 2}, nil},
+		{"wolf rider and wolf; wolf", nil, map[string]int{"Wolf Rider": 1, "Wolf": 2}, nil},
+		{"2 fire and ice", nil, map[string]int{"Fire and Ice": 2}, nil},
+		{"wlof 2", nil, map[string]int{"Wolf": 2}, nil},
+		{"all wolf, every bear", available, map[string]int{"Wolf": 4, "Bear": 1}, nil},
+		{"all wolf rider", available, map[string]int{}, nil},
+		{"all wolf, bear", nil, map[string]int{"Bear": 1}, []ParseError{{Pos: 1, Word: "all wolf", NoLibrary: true}}},
+		{"every wolf", nil, map[string]int{}, []ParseError{{Pos: 1, Word: "every wolf", NoLibrary: true}}},
+		{"wolf, dragon", nil, map[string]int{"Wolf": 1}, []ParseError{{Pos: 7, Word: "dragon"}}},
+	} {
+		cards, errors := parseCardList(test.input, test.available)
+		if !reflect.DeepEqual(cards, test.cards) || !reflect.DeepEqual(errors, test.errors) {
+			t.Errorf("parseCardList(%q) = %v, %v, want %v, %v", test.input, cards, errors, test.cards, test.errors)
+		}
+	}
+}
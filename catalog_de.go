@@ -0,0 +1,178 @@
+package main
+
+var catalogDE = Catalog{
+	"bot.revived": "Ich lebe wieder!",
+
+	"queue.dropped":     "Ich habe dich aus der Warteschlange entfernt, weil du nicht da warst, als du an der Reihe warst.",
+	"queue.finished":    "Handel beendet.",
+	"queue.your_turn":   "Du bist jetzt an der Reihe! Ich schicke dir eine Handelseinladung.",
+	"queue.now_trading": "Handel mit [{{.Partner}}]{{if .Waiting}} < {{join .Waiting \" < \"}}{{else}}.{{end}}",
+	"queue.already":     "Du stehst bereits in der Warteschlange. Deine Position ist {{.Position}}.",
+	"queue.added":       "{{if .Mention}}{{.Mention}}: {{end}}Du stehst jetzt in der Warteschlange. Deine Position ist {{.Position}}.",
+
+	"limit.daily":    "Du hast {{.Command}} heute zu oft benutzt. Bitte komm morgen wieder.",
+	"limit.cooldown": "Bitte warte {{.Seconds}} Sekunden, bevor du {{.Command}} wieder benutzt.",
+	"acl.banned": "Du wurdest für {{.Duration}} gesperrt, weil du" +
+		"{{if eq .Reason \"timeout\"}} wiederholt Handel verfallen lassen hast.{{else}} meine Limits wiederholt ignoriert hast.{{end}}",
+
+	"lang.current": "Ich spreche Deutsch mit dir. Verfügbare Sprachen: {{join .Available \", \"}}. Mit '!lang [Sprache]' kannst du sie ändern.",
+	"lang.set":     "Alles klar, ab jetzt spreche ich Deutsch mit dir.",
+	"lang.unknown": "Ich spreche kein '{{.Language}}'. Verfügbare Sprachen: {{join .Available \", \"}}.",
+
+	"list.needed": "Du musst diesem Befehl eine Liste von Karten anhängen, getrennt durch Kommas. Multiplikatoren wie '2x' oder 'all'" +
+		" und Platzhalter wie 'every decay rare' sind erlaubt.",
+	"wts.quote": "{{if .Quotes}}Ich {{if .TooPoor}}würde{{else}}werde{{end}} {{join .Quotes \", \"}} zahlen.{{if gt (len .Quotes) 1}} Das macht zusammen {{.Sum}}g.{{end}}" +
+		"{{if .TooPoor}} Ich habe im Moment nur {{.Budget}}g.{{end}}{{if .Capped}} Ich kaufe höchstens {{join .Capped \", \"}}.{{end}}{{end}}{{if .Refused}}{{if .Quotes}} {{end}}Ich kaufe keine weiteren {{join .Refused \", \"}}.{{end}}" +
+		"{{if .Problems}} {{.Problems}}{{end}}",
+	"wtb.none": "{{if .Card}}Ich habe {{.Card}} nicht auf Lager.{{else}}Ich habe nichts von dieser Liste auf Lager.{{end}}" +
+		"{{if .Card}} Flüster mir '!notify {{.Card}}' und ich sage dir Bescheid, sobald ich sie habe.{{end}}{{if .Problems}} {{.Problems}}{{end}}",
+	"wtb.quote": "Ich möchte {{join .Quotes \", \"}} haben.{{if .Partial}} Mehr habe ich nicht.{{end}}{{if gt (len .Quotes) 1}} Das macht zusammen {{.Sum}}g.{{end}}" +
+		"{{if .Problems}} {{.Problems}}{{end}}",
+
+	"parse.unknown":    "Ich weiß nicht, was {{range $i, $e := .Errors}}{{if $i}}, {{end}}'{{$e.Word}}' (an Position {{$e.Pos}}){{end}} ist.",
+	"parse.no_library": "Ich kann deine Sammlung nicht sehen und weiß daher nicht, wie viele '{{.Word}}' sind. Bitte gib eine Anzahl an.",
+	"card.ambiguous":   "'{{.Word}}'{{if .Pos}} (an Position {{.Pos}}){{end}} ist mehrdeutig: meinst du {{join .Options \" oder \"}}?",
+	"alias.set":        "'{{.Alias}}' bedeutet jetzt {{.Card}}.",
+	"alias.removed":    "'{{.Alias}}' bedeutet jetzt nichts mehr.",
+	"alias.usage":      "Benutzung: !alias [Spitzname] = [Karte], oder !unalias [Spitzname].",
+
+	"card.info": "{{.Name}}: {{.RarityName}} {{.Resource}} {{.Kind}}{{if .SubTypes}} ({{.SubTypes}}){{end}}, Kosten {{.Cost}}" +
+		"{{if eq .Kind \"creature\" \"structure\"}}, {{.Ap}} Angriff, {{.Ac}} Countdown, {{.Hp}} Leben{{end}}, Set {{.Set}}." +
+		"{{if .Description}} {{.Description}}{{end}}",
+	"search.result":  "{{.Count}} Karten passen: {{join .Names \", \"}}{{if .More}} und {{.More}} weitere{{end}}.",
+	"search.none":    "Keine Karte passt zu diesem Filter.",
+	"search.invalid": "{{if .Word}}Ich verstehe '{{.Word}}' nicht. {{end}}Versuch es mit etwas wie '!search decay creature cost:2-4 set:1 rarity:rare'.",
+
+	"library.unavailable": "Ich kann deine Sammlung gerade nicht sehen. Bitte häng diesem Befehl eine Liste von Karten an, getrennt durch Kommas.",
+	"wts.nothing_wanted":  "In deiner Sammlung ist gerade nichts, wonach ich suche.",
+	"compare.unknown":     "Ich kann die Sammlung von '{{.Player}}' nicht sehen.",
+	"compare.result": "{{.Player}} hat {{len .Theirs}} Kartentypen, die ich nicht habe{{if .Theirs}}: {{join .Theirs \", \"}}{{end}}." +
+		" Ich habe {{len .Ours}} Kartentypen, die {{.Player}} nicht besitzt{{if .Ours}}: {{join .Ours \", \"}}{{end}}. Wir handeln beide {{.Both}} Kartentypen.",
+
+	"complete.none": "Du besitzt schon jede Karte, die ich dir dort verkaufen könnte.",
+	"complete.quote": "Dir fehlen {{.Count}} Kartentypen, die ich vorrätig habe: {{join .Names \", \"}}{{if .More}} und {{.More}} weitere{{end}}." +
+		" Je eine davon kostet {{.Sum}}g{{if .Discount}} ({{.Discount}}g Rabatt in den nächsten {{.Minutes}} Minuten){{end}}. Schreib !trade und ich lege sie ins Handelsfenster.",
+
+	"reserve.list": "{{if .Cards}}Ich halte {{join .Cards \", \"}} für dich zurück.{{else}}Ich halte keine Karten für dich zurück.{{end}}",
+	"reserve.held": "{{if .Cards}}Ich halte {{join .Cards \", \"}} für {{.Minutes}} Minuten für dich zurück. Stell dich mit '!trade' an, um sie zu bekommen.{{end}}" +
+		"{{if .Missing}} Ich konnte nicht alles von {{join .Missing \", \"}} zurückhalten.{{end}}{{if .Problems}} {{.Problems}}{{end}}",
+
+	"auction.unavailable": "Ich habe kein Exemplar von {{.Card}} mehr, das weder geschützt noch zurückgehalten ist.",
+	"auction.usage":       "Benutzung: '!auction <Karte> <Mindestpreis> <Minuten>'.",
+	"auction.opened":      "Auktion! Ich verkaufe {{.Card}} in den nächsten {{.Minutes}} Minuten an den Höchstbietenden, Gebote ab {{.Reserve}}g. Flüster mir '!bid {{.Card}} <Gold>', um zu bieten.",
+	"auction.list":        "{{if .Auctions}}Laufende Auktionen: {{range $i, $a := .Auctions}}{{if $i}}, {{end}}{{$a.Card}} (Gebote ab {{$a.MinimumBid}}g){{end}}.{{else}}Gerade laufen keine Auktionen.{{end}}",
+	"auction.bid_usage":   "Benutzung: '!bid [Karte] <Gold>'. Mit '!auctions' siehst du, was versteigert wird.",
+	"auction.which":       "Diese Auktion gibt es nicht. Mit '!auctions' siehst du, was versteigert wird.",
+	"auction.too_low":     "Gebote für {{.Card}} beginnen bei {{.Minimum}}g.",
+	"auction.bid_placed":  "Du bist mit {{.Amount}}g Höchstbietender für {{.Card}}. Die Auktion endet in {{.Ends}} Minuten.",
+	"auction.outbid":      "Du wurdest bei {{.Card}} mit {{.Amount}}g überboten. Flüster mir '!bid {{.Card}} {{.Minimum}}', um erneut zu bieten.",
+	"auction.unsold":      "Die Auktion für {{.Card}} ist ohne Gebote zu Ende gegangen.",
+	"auction.won":         "{{.Winner}} hat die Auktion für {{.Card}} mit {{.Price}}g gewonnen!",
+	"auction.you_won": "Du hast {{.Card}} für {{.Price}}g ersteigert! Ich halte die Karte {{.Minutes}} Minuten für dich zurück." +
+		"{{if .Trading}} Schreib '!add {{.Card}}' in unserem Handel, um sie zu bekommen.{{else if gt .Position 0}} Du bist auf Platz {{.Position}} der Warteschlange." +
+		"{{else}} Ich lade dich gleich zum Handeln ein.{{end}}",
+
+	"raffle.usage":       "Benutzung: '!raffle <Liste von Karten> [Minuten]'.",
+	"raffle.opened":      "Verlosung! Flüster mir in den nächsten {{.Minutes}} Minuten '!enter', um {{join .Cards \", \"}} zu gewinnen. Hash des Seeds: {{.Hash}}",
+	"raffle.none":        "Gerade läuft keine Verlosung.",
+	"raffle.entered":     "Du nimmst an der Verlosung von {{join .Cards \", \"}} teil. Viel Glück!",
+	"raffle.no_entrants": "Niemand hat an der Verlosung von {{join .Cards \", \"}} teilgenommen.",
+	"raffle.closed":      "Die Verlosung von {{join .Cards \", \"}} ist geschlossen. Teilnehmer in Ziehungsreihenfolge: {{join .Entrants \",\"}}. Hash des Seeds: {{.Hash}}",
+	"raffle.drawn": "{{.Winner}} hat {{join .Cards \", \"}} unter {{.Count}} Teilnehmern gewonnen! Seed: {{.Seed}}." +
+		" Die ersten 8 Bytes von sha256(Seed + \":\" + Teilnehmer) modulo {{.Count}} ergeben den Gewinner, gezählt ab 0.",
+	"raffle.you_won": "Du hast {{join .Cards \", \"}} bei der Verlosung gewonnen! Ich halte deinen Gewinn {{.Minutes}} Minuten für dich zurück." +
+		"{{if .Trading}} Füg deinen Gewinn mit '!add' in unserem Handel hinzu.{{else if gt .Position 0}} Du bist auf Platz {{.Position}} der Warteschlange.{{else}} Ich lade dich gleich zum Handeln ein.{{end}}",
+
+	"order.usage":     "Benutzung: '!order [Anzahl] <Karte> <Höchstpreis>', '!orders' zum Auflisten, oder '!cancelorder <Karte>'.",
+	"order.too_many":  "Du kannst nicht mehr als {{.Max}} Aufträge haben.",
+	"order.placed":    "Auftrag erteilt: {{if ne .Num 1}}{{.Num}}x {{end}}{{.Card}} für höchstens {{.MaxPrice}}g pro Stück.",
+	"order.cancelled": "Dein Auftrag für {{.Card}} ist storniert.",
+	"order.list": "{{if .Orders}}Deine Aufträge: {{range $i, $o := .Orders}}{{if $i}}, {{end}}{{if ne $o.Num 1}}{{$o.Num}}x {{end}}{{$o.Card}} ≤{{$o.MaxPrice}}g{{if $o.Paused}} (pausiert){{end}}{{end}}." +
+		"{{else}}Du hast keine Aufträge.{{end}}",
+	"order.paused": "Du hast deine {{.Card}} {{.Misses}} Mal nicht abgeholt, deshalb ist der Auftrag pausiert. Erteile ihn erneut, um ihn fortzusetzen.",
+	"order.filled": "Dein Auftrag passt: Ich halte {{if ne .Num 1}}{{.Num}}x {{end}}{{.Card}} für {{.Price}}g für {{.Minutes}} Minuten für dich zurück." +
+		"{{if .Trading}} Schreib '!add {{.Card}}' in unserem Handel, um sie zu bekommen.{{else if gt .Position 0}} Du bist auf Platz {{.Position}} der Warteschlange." +
+		"{{else if eq .Position 0}} Ich lade dich gleich zum Handeln ein.{{end}}",
+
+	"notify.usage": "Benutzung: '!notify <Karte> [Höchstpreis] [queue]', oder '!unnotify <Karte>' zum Beenden.",
+	"notify.added": "Ich sage dir Bescheid, sobald ich {{.Card}}{{if .MaxPrice}} für höchstens {{.MaxPrice}}g{{end}} habe." +
+		"{{if .Queue}} Dann stelle ich dich auch in die Warteschlange.{{end}}",
+	"notify.removed": "Ich sage dir nicht mehr Bescheid wegen {{.Card}}.",
+	"notify.list":    "{{if .Cards}}Du wartest auf {{join .Cards \", \"}}.{{else}}Du wartest auf keine Karten.{{end}}",
+	"notify.available": "Ich habe jetzt {{.Card}} für {{.Price}}g auf Lager!" +
+		"{{if ge .Position 0}} Ich habe dich in die Warteschlange gestellt{{if .Position}} auf Platz {{.Position}}{{end}}.{{end}}",
+
+	"targets.report": "{{.Count}} Karten liegen außerhalb ihres Bereichs. Unter dem Ziel: {{if .Below}}{{join .Below \", \"}}{{else}}keine{{end}}." +
+		" Über dem Maximum: {{if .Above}}{{join .Above \", \"}}{{else}}keine{{end}}. An der Obergrenze: {{if .AtCap}}{{join .AtCap \", \"}}{{else}}keine{{end}}.",
+
+	"price.unknown": "Es gibt keine Karte namens '{{.Card}}'.",
+	"price.out_of_stock": "{{.Card}} ist ausverkauft. {{if .TooPoor}}Ich würde für {{.Buy}}g kaufen, aber so viel habe ich nicht" +
+		"{{else}}Ich kaufe für {{.Buy}}g{{end}} (Grundwert {{.Base}}g).",
+	"price.quote": "Ich kaufe {{.Card}} für {{.Buy}}g und verkaufe für {{.Sell}}g (Grundwert {{.Base}}g, {{.Stocked}} auf Lager).",
+	"missing":     "Mir fehlen im Moment {{join .Cards \", \"}}. Dafür zahle ich extra!",
+	"stock": "Ich habe {{.Commons}} Commons, {{.Uncommons}} Uncommons und {{.Rares}} Rares. Das sind {{.Percent}}% aller Kartentypen," +
+		" dazu {{.Gold}} Gold. Der Gesamtwert beträgt {{.ValueK}}k Gold.",
+	"help": "Du kannst mir WTS- oder WTB-Anfragen flüstern. Wenn du handeln möchtest, stell dich mit '!trade' an." +
+		" Du kannst auch meinen '!stock' ansehen oder mit '!lang' meine Sprache ändern.",
+	"uptime":           "Online seit {{.Duration}}.",
+	"whisper.reminder": "Um den Kanal nicht zuzuspammen, benutze diesen Befehl bitte nur im Flüstern. Übrigens funktionieren dort auch alle anderen Befehle!",
+
+	"trade.acquired":  "Ich habe gerade {{join .Cards \", \"}} erworben.",
+	"trade.sold_last": "Ich habe gerade mein letztes {{join .Cards \", \"}} verkauft.",
+	"trade.welcome":   "Willkommen {{.Partner}}. Ich bin ein automatischer Händler. Wenn du nicht weiterweißt, sag einfach '!help'.",
+	"trade.wtb_init":  "Ich habe den Handel mit deiner letzten WTB-Anfrage vorbereitet. Mit !reset kannst du das rückgängig machen.",
+	"trade.help": "Leg einfach die Karten, die du verkaufen willst, auf deine Seite. Um Karten von mir zu kaufen, sag 'wtb [Liste von Karten]'" +
+		" und ich lege alles auf, was ich davon habe. Mit !add und !remove kannst du auch einzelne Karten hinzufügen oder entfernen." +
+		" Unsicher wegen des Goldes? Frag einfach nach dem !price und ich liste alles auf. Wenn ich mir dein Angebot nicht leisten kann, frag mich nach einem !suggest. Mit !swap tauschen wir Karten gegen Karten ohne Gold.",
+	"trade.donation_on": "Ich betrachte alles, was du in diesen Handel legst, als Spende. Vielen Dank!" +
+		" Wenn du es dir anders überlegst, wiederhole einfach den Befehl.",
+	"trade.donation_off": "Okay :(",
+	"trade.price": "{{if .Buy}}Ich kaufe {{range $i, $c := .Buy}}{{if $i}}, {{end}}{{$c.Name}} für {{$c.Price}}g{{end}}. {{end}}" +
+		"{{if .Sell}}Ich verkaufe {{range $i, $c := .Sell}}{{if $i}}, {{end}}{{$c.Name}} für {{$c.Price}}g{{end}}. {{end}}" +
+		"{{if .Discount}}Du bekommst {{.Discount}}g Rabatt für das Vervollständigen deiner Sammlung. {{end}}" +
+		"{{if .TheyOwe}}Also schuldest du mir {{.Owed}}g.{{else}}Also schulde ich dir {{.Owed}}g.{{end}}",
+	"trade.add_failed": "{{if .Missing}}Ich habe {{join .Missing \", \"}} nicht.{{end}}" +
+		"{{if .Problems}}{{if .Missing}} {{end}}{{.Problems}}{{end}}",
+	"trade.remove_which":  "Du musst mir sagen, welche Karte ich entfernen soll.",
+	"trade.no_such_card":  "Es gibt keine Karte namens '{{.Card}}'.",
+	"trade.not_in_trade":  "{{.Card}} ist nicht Teil dieses Handels!",
+	"trade.thanks":        "Danke!",
+	"trade.donated":       "{{.Partner}} hat gerade Karten im Wert von {{.Value}}g gespendet. Ein Hoch auf sie!",
+	"trade.idle":          "Du warst eine Minute lang inaktiv. Dieses Handelsfenster schließt sich in 30 Sekunden, wenn du nichts tust.",
+	"trade.times_up":      "Die Zeit ist um!",
+	"trade.one_minute":    "Bitte schließe den Handel innerhalb der nächsten Minute ab.",
+	"trade.ten_seconds":   "Du hast noch 10 Sekunden, um den Handel abzuschließen.",
+	"trade.too_expensive": "Tut mir leid - ich habe nur {{.Budget}} Gold zur Verfügung. Bitte nimm etwas heraus. Oder ist das eine !donation?",
+	"trade.suggest": "{{if .Remove}}Ich könnte dein Angebot ohne {{join .Remove \", \"}} annehmen{{if .KeepOwed}}, wenn du mir {{.KeepOwed}}g zahlst{{else}} und dir {{.KeepGold}}g zahlen{{end}}. {{end}}" +
+		"{{if .Add}}{{if .Remove}}Oder ich lege{{else}}Wie wäre es, wenn ich{{end}} {{join .Add \", \"}}{{if .AddGold}} und {{.AddGold}}g{{end}} {{if .Remove}}dazu{{else}}dazulege{{end}}? Sag '!ok' und ich lege sie hinein.{{end}}",
+	"trade.suggest_remove": "Bitte nimm {{join .Remove \", \"}} aus dem Handel.",
+	"trade.no_suggestion":  "Ich habe kein besseres Angebot für dich.",
+	"trade.swap_on":        "Tauschmodus: Ich tausche Karten gegen Karten ohne Gold, wenn die Werte höchstens {{.Tolerance}}% auseinanderliegen.",
+	"trade.swap_off":       "Der Tauschmodus ist aus, ich gleiche den Handel wieder mit Gold aus.",
+	"trade.swap_values": "{{if .Buy}}Deine Karten sind mir {{range $i, $c := .Buy}}{{if $i}}, {{end}}{{$c.Name}} {{$c.Price}}g{{end}} = {{.Their}}g wert. {{end}}" +
+		"{{if .Sell}}Meine sind {{range $i, $c := .Sell}}{{if $i}}, {{end}}{{$c.Name}} {{$c.Price}}g{{end}} = {{.Mine}}g wert. {{end}}" +
+		"{{if .Fair}}Das ist ein fairer Tausch!{{else}}Das liegt mehr als {{.Tolerance}}% auseinander, bitte pass die Karten an.{{end}}",
+	"trade.summary": "Zusammenfassung - du gibst: {{range $i, $l := .Buy}}{{if $i}}, {{end}}{{if ne $l.Num 1}}{{$l.Num}}x {{end}}{{$l.Card}} für {{$l.Total}}g{{if ne $l.Num 1}} zusammen{{end}}{{end}}" +
+		"{{if .TheirGold}}{{if .Buy}}, {{end}}{{.TheirGold}}g{{end}}{{if not (or .Buy .TheirGold)}}nichts{{end}}." +
+		" Ich gebe: {{range $i, $l := .Sell}}{{if $i}}, {{end}}{{if ne $l.Num 1}}{{$l.Num}}x {{end}}{{$l.Card}} für {{$l.Total}}g{{if ne $l.Num 1}} zusammen{{end}}{{end}}" +
+		"{{if .MyGold}}{{if .Sell}}, {{end}}{{.MyGold}}g{{end}}{{if not (or .Sell .MyGold)}}nichts{{end}}." +
+		" Sag !confirm{{if .Seconds}} oder warte {{.Seconds}} Sekunden{{end}} und ich nehme an.",
+	"trade.summary_changed":    "Der Handel hat sich seit meiner Zusammenfassung geändert, bitte warte auf die neue.",
+	"trade.nothing_to_confirm": "Es gibt noch nichts zu bestätigen.",
+	"trade.over_cap":           "Ich kaufe keine weiteren {{join .Cards \", \"}}, bitte nimm {{if gt (len .Cards) 1}}sie{{else}}die Karte{{end}} heraus.",
+	"trade.settlement": "Deine Karten sind mir {{.Their}}g wert, meine {{.Mine}}g. " +
+		"{{if .TheyOwe}}Bitte leg {{if .TheirGold}}noch {{end}}{{.TheyOwe}}g dazu.{{else if .Overpaid}}Du hast {{.Overpaid}}g mehr hineingelegt, als ich zurückzahlen kann, bitte nimm es heraus." +
+		"{{else if .Refund}}Du hast {{.Refund}}g zu viel hineingelegt, ich gebe es dir zurück.{{else if .MyGold}}Ich zahle dir {{.MyGold}}g{{if .TheirGold}}, darin sind deine {{.TheirGold}}g enthalten{{end}}.{{else}}Wir sind quitt.{{end}}",
+
+	"announce.demand": "Ich suche {{range $i, $c := .Cards}}{{if $i}}, {{end}}{{$c.Name}} ({{$c.Price}}g){{end}}." +
+		" Flüstere mir 'wts [Liste von Karten]' für ein Angebot!",
+	"announce.acquired": "Frisch eingetroffen: {{range $i, $c := .Cards}}{{if $i}}, {{end}}{{$c.Name}}{{end}}." +
+		" Flüstere mir 'wtb [Liste von Karten]', um die Preise zu erfahren.",
+	"announce.missing": "Mir fehlen im Moment {{range $i, $c := .Cards}}{{if $i}}, {{end}}{{$c.Name}}{{end}}." +
+		" Dafür zahle ich extra! Flüstere mir '!missing' für die ganze Liste.",
+	"announce.queue": "{{if .Current}}Ich handle gerade mit {{.Current}}{{if .Waiting}} < {{join .Waiting \" < \"}}{{end}}." +
+		"{{else}}Ich bin gerade frei zum Handeln.{{end}} Sag '!trade', um dich anzustellen.",
+	"announce.hint": "Du kannst mir 'wtb/wts [Liste von Karten]' flüstern, um Preise und Verfügbarkeit" +
+		" aller Karten zu erfahren, die dich interessieren.",
+	"announce.auction": "Zur Versteigerung: {{range $i, $c := .Cards}}{{if $i}}, {{end}}{{$c.Name}} (Gebote ab {{$c.Price}}g){{end}}. Flüster mir '!bid <Karte> <Gold>', um zu bieten.",
+}
@@ -0,0 +1,178 @@
+package main
+
+var catalogEN = Catalog{
+	"bot.revived": "I live again!",
+
+	"queue.dropped":     "I've removed you from the trade queue because you weren't around when it was your turn.",
+	"queue.finished":    "Finished trading.",
+	"queue.your_turn":   "It's your turn now! I'm sending you a trade invite.",
+	"queue.now_trading": "Now trading with [{{.Partner}}]{{if .Waiting}} < {{join .Waiting \" < \"}}{{else}}.{{end}}",
+	"queue.already":     "You are already queued for trading. Your position in the queue is {{.Position}}.",
+	"queue.added":       "{{if .Mention}}{{.Mention}}: {{end}}You are now queued for trading. Your position in the queue is {{.Position}}.",
+
+	"limit.daily":    "You've used {{.Command}} too often today. Please come back tomorrow.",
+	"limit.cooldown": "Please wait {{.Seconds}} seconds before using {{.Command}} again.",
+	"acl.banned": "You have been temporarily banned for {{.Duration}} because you" +
+		"{{if eq .Reason \"timeout\"}} kept letting trades time out.{{else}} kept ignoring my limits.{{end}}",
+
+	"lang.current": "I'm talking to you in English. Available languages: {{join .Available \", \"}}. Use '!lang [language]' to change it.",
+	"lang.set":     "Okay, I'll talk to you in English from now on.",
+	"lang.unknown": "I don't speak '{{.Language}}'. Available languages: {{join .Available \", \"}}.",
+
+	"list.needed": "You need to add a list of cards to this command, separated by commas. Multipliers like '2x' or 'all'" +
+		" and wildcards like 'every decay rare' are allowed.",
+	"wts.quote": "{{if .Quotes}}I {{if .TooPoor}}would{{else}}will{{end}} pay {{join .Quotes \", \"}}.{{if gt (len .Quotes) 1}} That sums up to {{.Sum}}g.{{end}}" +
+		"{{if .TooPoor}} I currently only have {{.Budget}}g.{{end}}{{if .Capped}} I'll buy no more than {{join .Capped \", \"}}.{{end}}{{end}}{{if .Refused}}{{if .Quotes}} {{end}}I'm not buying any more {{join .Refused \", \"}}.{{end}}" +
+		"{{if .Problems}} {{.Problems}}{{end}}",
+	"wtb.none": "I don't have {{if .Card}}{{.Card}}{{else}}anything on that list{{end}} stocked." +
+		"{{if .Card}} Whisper me '!notify {{.Card}}' and I'll tell you when I get it.{{end}}{{if .Problems}} {{.Problems}}{{end}}",
+	"wtb.quote": "I want to have {{join .Quotes \", \"}}.{{if .Partial}} That's all I have.{{end}}{{if gt (len .Quotes) 1}} That sums up to {{.Sum}}g.{{end}}" +
+		"{{if .Problems}} {{.Problems}}{{end}}",
+
+	"parse.unknown":    "I don't know what {{range $i, $e := .Errors}}{{if $i}}, {{end}}'{{$e.Word}}' (at position {{$e.Pos}}){{end}} is.",
+	"parse.no_library": "I can't see your collection, so I don't know how many copies '{{.Word}}' means. Please name a number instead.",
+	"card.ambiguous":   "'{{.Word}}'{{if .Pos}} (at position {{.Pos}}){{end}} is ambiguous: did you mean {{join .Options \" or \"}}?",
+	"alias.set":        "'{{.Alias}}' now means {{.Card}}.",
+	"alias.removed":    "'{{.Alias}}' doesn't mean anything anymore.",
+	"alias.usage":      "Usage: !alias [nickname] = [card], or !unalias [nickname].",
+
+	"card.info": "{{.Name}}: {{.RarityName}} {{.Resource}} {{.Kind}}{{if .SubTypes}} ({{.SubTypes}}){{end}}, cost {{.Cost}}" +
+		"{{if eq .Kind \"creature\" \"structure\"}}, {{.Ap}} attack, {{.Ac}} countdown, {{.Hp}} health{{end}}, set {{.Set}}." +
+		"{{if .Description}} {{.Description}}{{end}}",
+	"search.result":  "{{.Count}} cards match: {{join .Names \", \"}}{{if .More}} and {{.More}} more{{end}}.",
+	"search.none":    "No card matches that filter.",
+	"search.invalid": "{{if .Word}}I don't understand '{{.Word}}'. {{end}}Try something like '!search decay creature cost:2-4 set:1 rarity:rare'.",
+
+	"library.unavailable": "I can't see your collection right now. Please add a list of cards to this command, separated by commas.",
+	"wts.nothing_wanted":  "There's nothing in your collection that I'm looking for right now.",
+	"compare.unknown":     "I can't see the collection of '{{.Player}}'.",
+	"compare.result": "{{.Player}} has {{len .Theirs}} card types I don't have{{if .Theirs}}: {{join .Theirs \", \"}}{{end}}." +
+		" I have {{len .Ours}} card types they don't own{{if .Ours}}: {{join .Ours \", \"}}{{end}}. We both trade {{.Both}} card types.",
+
+	"complete.none": "You already own every card I could sell you there.",
+	"complete.quote": "You're missing {{.Count}} card types I have in stock: {{join .Names \", \"}}{{if .More}} and {{.More}} more{{end}}." +
+		" One of each costs {{.Sum}}g{{if .Discount}} ({{.Discount}}g off for the next {{.Minutes}} minutes){{end}}. Type !trade and I'll put them in the trade window.",
+
+	"reserve.list": "{{if .Cards}}I'm holding {{join .Cards \", \"}} for you.{{else}}I'm not holding any cards for you.{{end}}",
+	"reserve.held": "{{if .Cards}}I'm holding {{join .Cards \", \"}} for you for {{.Minutes}} minutes. Queue up with '!trade' to get them.{{end}}" +
+		"{{if .Missing}} I couldn't hold all of {{join .Missing \", \"}}.{{end}}{{if .Problems}} {{.Problems}}{{end}}",
+
+	"auction.unavailable": "I have no copy of {{.Card}} left to auction that isn't protected or reserved.",
+	"auction.usage":       "Usage: '!auction <card> <reserve price> <minutes>'.",
+	"auction.opened":      "Auction! I'm selling {{.Card}} to the highest bidder in the next {{.Minutes}} minutes, bids from {{.Reserve}}g. Whisper me '!bid {{.Card}} <gold>' to bid.",
+	"auction.list":        "{{if .Auctions}}Running auctions: {{range $i, $a := .Auctions}}{{if $i}}, {{end}}{{$a.Card}} (bids from {{$a.MinimumBid}}g){{end}}.{{else}}There are no auctions right now.{{end}}",
+	"auction.bid_usage":   "Usage: '!bid [card] <gold>'. See '!auctions' for what's up for auction.",
+	"auction.which":       "There's no such auction. See '!auctions' for what's up for auction.",
+	"auction.too_low":     "Bids for {{.Card}} start at {{.Minimum}}g.",
+	"auction.bid_placed":  "You're the highest bidder for {{.Card}} with {{.Amount}}g. The auction ends in {{.Ends}} minutes.",
+	"auction.outbid":      "You've been outbid on {{.Card}} with {{.Amount}}g. Whisper me '!bid {{.Card}} {{.Minimum}}' to bid again.",
+	"auction.unsold":      "The auction for {{.Card}} ended without bids.",
+	"auction.won":         "{{.Winner}} won the auction for {{.Card}} with {{.Price}}g!",
+	"auction.you_won": "You won {{.Card}} for {{.Price}}g! I'm holding it for you for {{.Minutes}} minutes." +
+		"{{if .Trading}} Type '!add {{.Card}}' in our trade to get it.{{else if gt .Position 0}} You're at position {{.Position}} in the trade queue." +
+		"{{else}} I'll invite you to trade.{{end}}",
+
+	"raffle.usage":       "Usage: '!raffle <list of cards> [minutes]'.",
+	"raffle.opened":      "Giveaway! Whisper me '!enter' in the next {{.Minutes}} minutes for a chance to win {{join .Cards \", \"}}. Seed hash: {{.Hash}}",
+	"raffle.none":        "There's no giveaway running right now.",
+	"raffle.entered":     "You're in the draw for {{join .Cards \", \"}}. Good luck!",
+	"raffle.no_entrants": "Nobody entered the giveaway for {{join .Cards \", \"}}.",
+	"raffle.closed":      "The giveaway for {{join .Cards \", \"}} is closed. Entrants in draw order: {{join .Entrants \",\"}}. Seed hash: {{.Hash}}",
+	"raffle.drawn": "{{.Winner}} won {{join .Cards \", \"}} out of {{.Count}} entrants! Seed: {{.Seed}}." +
+		" The first 8 bytes of sha256(seed + \":\" + entrants) modulo {{.Count}} give the winner, counting from 0.",
+	"raffle.you_won": "You won {{join .Cards \", \"}} in the giveaway! I'm holding your prize for {{.Minutes}} minutes." +
+		"{{if .Trading}} Type '!add' with your prize in our trade to get it.{{else if gt .Position 0}} You're at position {{.Position}} in the trade queue.{{else}} I'll invite you to trade.{{end}}",
+
+	"order.usage":     "Usage: '!order [quantity] <card> <max price>', '!orders' to list them, or '!cancelorder <card>'.",
+	"order.too_many":  "You can't have more than {{.Max}} orders.",
+	"order.placed":    "Order placed: {{if ne .Num 1}}{{.Num}}x {{end}}{{.Card}} for {{.MaxPrice}}g or less each.",
+	"order.cancelled": "Your order for {{.Card}} is cancelled.",
+	"order.list": "{{if .Orders}}Your orders: {{range $i, $o := .Orders}}{{if $i}}, {{end}}{{if ne $o.Num 1}}{{$o.Num}}x {{end}}{{$o.Card}} ≤{{$o.MaxPrice}}g{{if $o.Paused}} (paused){{end}}{{end}}." +
+		"{{else}}You have no orders.{{end}}",
+	"order.paused": "You didn't pick up your {{.Card}} {{.Misses}} times, so I paused that order. Place it again to resume it.",
+	"order.filled": "Your order matched: I'm holding {{if ne .Num 1}}{{.Num}}x {{end}}{{.Card}} for {{.Price}}g for you for {{.Minutes}} minutes." +
+		"{{if .Trading}} Type '!add {{.Card}}' in our trade to get it.{{else if gt .Position 0}} You're at position {{.Position}} in the trade queue." +
+		"{{else if eq .Position 0}} I'll invite you to trade.{{end}}",
+
+	"notify.usage": "Usage: '!notify <card> [max price] [queue]', or '!unnotify <card>' to stop.",
+	"notify.added": "I'll let you know when I have {{.Card}}{{if .MaxPrice}} for {{.MaxPrice}}g or less{{end}}." +
+		"{{if .Queue}} I'll also put you in the trade queue then.{{end}}",
+	"notify.removed": "I won't tell you about {{.Card}} anymore.",
+	"notify.list":    "{{if .Cards}}You're waiting for {{join .Cards \", \"}}.{{else}}You're not waiting for any cards.{{end}}",
+	"notify.available": "I have {{.Card}} in stock now for {{.Price}}g!" +
+		"{{if ge .Position 0}} I've put you in the trade queue{{if .Position}} at position {{.Position}}{{end}}.{{end}}",
+
+	"targets.report": "{{.Count}} cards are outside their band. Below target: {{if .Below}}{{join .Below \", \"}}{{else}}none{{end}}." +
+		" Above max: {{if .Above}}{{join .Above \", \"}}{{else}}none{{end}}. At cap: {{if .AtCap}}{{join .AtCap \", \"}}{{else}}none{{end}}.",
+
+	"price.unknown": "There is no card named '{{.Card}}'.",
+	"price.out_of_stock": "{{.Card}} is out of stock. {{if .TooPoor}}I would buy for {{.Buy}}g, but I don't have that much" +
+		"{{else}}I'm buying for {{.Buy}}g{{end}} (base value {{.Base}}g).",
+	"price.quote": "I'm buying {{.Card}} for {{.Buy}}g and selling for {{.Sell}}g (base value {{.Base}}g, {{.Stocked}} stocked).",
+	"missing":     "I currently don't have {{join .Cards \", \"}}. I'm paying extra for that!",
+	"stock": "I have {{.Commons}} commons, {{.Uncommons}} uncommons and {{.Rares}} rares. That's {{.Percent}}% of all card types," +
+		" as well as {{.Gold}} gold. Total value is {{.ValueK}}k gold.",
+	"help": "You can whisper me WTS or WTB requests. If you're interested in trading, you can queue up with '!trade'." +
+		" You can also check the '!stock' or change my language with '!lang'.",
+	"uptime":           "Up since {{.Duration}}.",
+	"whisper.reminder": "To avoid spamming the channel, please use this command only in whisper. By the way, you can use any other command in whisper as well!",
+
+	"trade.acquired":  "I've just acquired {{join .Cards \", \"}}.",
+	"trade.sold_last": "I've just sold my last {{join .Cards \", \"}}.",
+	"trade.welcome":   "Welcome {{.Partner}}. This is an automated trading unit. If you don't know what to do, just say '!help'.",
+	"trade.wtb_init":  "I've initialized the trade room with your last WTB request. You can !reset to undo this.",
+	"trade.help": "Just add the scrolls you want to sell on your side. To buy scrolls from me, say 'wtb [list of scrolls]'" +
+		" and I'll add everything I have on that list. You can also !add or !remove single cards." +
+		" Not sure about the gold? Just ask for the !price and I'll list it up. If I can't afford your offer, ask me for a !suggest. Say !swap to trade cards for cards without gold.",
+	"trade.donation_on": "I will consider everything you put into this trade as a donation. Much appreciated!" +
+		" If you change your mind, just repeat the command.",
+	"trade.donation_off": "Okay :(",
+	"trade.price": "{{if .Buy}}I'll buy {{range $i, $c := .Buy}}{{if $i}}, {{end}}{{$c.Name}} for {{$c.Price}}g{{end}}. {{end}}" +
+		"{{if .Sell}}I'll sell {{range $i, $c := .Sell}}{{if $i}}, {{end}}{{$c.Name}} for {{$c.Price}}g{{end}}. {{end}}" +
+		"{{if .Discount}}You get {{.Discount}}g off for completing your collection. {{end}}" +
+		"{{if .TheyOwe}}Thus you owe me {{.Owed}}g.{{else}}Thus I owe you {{.Owed}}g.{{end}}",
+	"trade.add_failed": "{{if .Missing}}I don't have {{join .Missing \", \"}}.{{end}}" +
+		"{{if .Problems}}{{if .Missing}} {{end}}{{.Problems}}{{end}}",
+	"trade.remove_which":  "You have to name the card that I will remove.",
+	"trade.no_such_card":  "There is no scroll named '{{.Card}}'.",
+	"trade.not_in_trade":  "{{.Card}} is not part of this trade!",
+	"trade.thanks":        "Thanks!",
+	"trade.donated":       "{{.Partner}} just donated stuff worth {{.Value}}g. Praise to them!",
+	"trade.idle":          "You have been idle for a minute. This trade window will close in 30 seconds unless you interact with it.",
+	"trade.times_up":      "Time's up!",
+	"trade.one_minute":    "Please finish the trade within the next minute.",
+	"trade.ten_seconds":   "You have 10 seconds left to finish the trade.",
+	"trade.too_expensive": "Sorry - I only have {{.Budget}} gold at my disposal. Please take something out. Or is this a !donation?",
+	"trade.suggest": "{{if .Remove}}I could take your offer without {{join .Remove \", \"}} {{if .KeepOwed}}if you pay me {{.KeepOwed}}g{{else}}and pay you {{.KeepGold}}g{{end}}. {{end}}" +
+		"{{if .Add}}{{if .Remove}}Or{{else}}How about{{end}} I add {{join .Add \", \"}}{{if .AddGold}} and {{.AddGold}}g{{end}}? Say '!ok' and I'll put them in.{{end}}",
+	"trade.suggest_remove": "Please take {{join .Remove \", \"}} out of the trade.",
+	"trade.no_suggestion":  "I don't have a better offer for you.",
+	"trade.swap_on":        "Swap mode: I'll trade cards for cards without gold if the values are within {{.Tolerance}}% of each other.",
+	"trade.swap_off":       "Swap mode is off, I'll balance the trade with gold again.",
+	"trade.swap_values": "{{if .Buy}}Your cards are worth {{range $i, $c := .Buy}}{{if $i}}, {{end}}{{$c.Name}} {{$c.Price}}g{{end}} = {{.Their}}g to me. {{end}}" +
+		"{{if .Sell}}Mine are worth {{range $i, $c := .Sell}}{{if $i}}, {{end}}{{$c.Name}} {{$c.Price}}g{{end}} = {{.Mine}}g. {{end}}" +
+		"{{if .Fair}}That's a fair swap!{{else}}That's more than {{.Tolerance}}% apart, please adjust the cards.{{end}}",
+	"trade.summary": "Summary - you give: {{range $i, $l := .Buy}}{{if $i}}, {{end}}{{if ne $l.Num 1}}{{$l.Num}}x {{end}}{{$l.Card}} at {{$l.Total}}g{{if ne $l.Num 1}} total{{end}}{{end}}" +
+		"{{if .TheirGold}}{{if .Buy}}, {{end}}{{.TheirGold}}g{{end}}{{if not (or .Buy .TheirGold)}}nothing{{end}}." +
+		" I give: {{range $i, $l := .Sell}}{{if $i}}, {{end}}{{if ne $l.Num 1}}{{$l.Num}}x {{end}}{{$l.Card}} at {{$l.Total}}g{{if ne $l.Num 1}} total{{end}}{{end}}" +
+		"{{if .MyGold}}{{if .Sell}}, {{end}}{{.MyGold}}g{{end}}{{if not (or .Sell .MyGold)}}nothing{{end}}." +
+		" Say !confirm{{if .Seconds}} or wait {{.Seconds}} seconds{{end}} and I'll accept.",
+	"trade.summary_changed":    "The trade changed since my summary, please wait for the new one.",
+	"trade.nothing_to_confirm": "There's nothing to confirm yet.",
+	"trade.over_cap":           "I'm not buying any more {{join .Cards \", \"}}, please take {{if gt (len .Cards) 1}}them{{else}}it{{end}} out.",
+	"trade.settlement": "Your cards are worth {{.Their}}g to me, mine {{.Mine}}g. " +
+		"{{if .TheyOwe}}Please add {{.TheyOwe}}g{{if .TheirGold}} more{{end}}.{{else if .Overpaid}}You've put in {{.Overpaid}}g more than I can pay back, please take it out." +
+		"{{else if .Refund}}You've put in {{.Refund}}g too much, I'm giving it back.{{else if .MyGold}}I'm paying you {{.MyGold}}g{{if .TheirGold}}, which includes your {{.TheirGold}}g back{{end}}.{{else}}We're even.{{end}}",
+
+	"announce.demand": "I'm looking for {{range $i, $c := .Cards}}{{if $i}}, {{end}}{{$c.Name}} ({{$c.Price}}g){{end}}." +
+		" Whisper me 'wts [list of cards]' for a quote!",
+	"announce.acquired": "Freshly stocked: {{range $i, $c := .Cards}}{{if $i}}, {{end}}{{$c.Name}}{{end}}." +
+		" Whisper me 'wtb [list of cards]' to check prices.",
+	"announce.missing": "I currently don't have {{range $i, $c := .Cards}}{{if $i}}, {{end}}{{$c.Name}}{{end}}." +
+		" I'm paying extra for that! Whisper me '!missing' for the full list.",
+	"announce.queue": "{{if .Current}}I'm trading with {{.Current}}{{if .Waiting}} < {{join .Waiting \" < \"}}{{end}}." +
+		"{{else}}I'm free to trade right now.{{end}} Say '!trade' to queue up.",
+	"announce.hint": "You can whisper me with 'wtb/wts [list of cards]' to easily check prices and availability" +
+		" for all cards you're interested in.",
+	"announce.auction": "Up for auction: {{range $i, $c := .Cards}}{{if $i}}, {{end}}{{$c.Name}} (bids from {{$c.Price}}g){{end}}. Whisper me '!bid <card> <gold>' to bid.",
+}
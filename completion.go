@@ -0,0 +1,66 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+type completionOffer struct {
+	Cards   map[string]int
+	Expires time.Time
+}
+
+var completionOffers = struct {
+	sync.Mutex
+	offers map[Player]completionOffer
+}{offers: make(map[Player]completionOffer)}
+
+// CompletionFor lists one of each card type matching the filter that the player doesn't own and we have in stock.
+func CompletionFor(player Player, f CardFilter) map[string]int {
+	owned := OwnedCards(player)
+	available := Reservations.Available(player)
+	cards := make(map[string]int)
+	for _, card := range Cards.Filter(f) {
+		if owned[card.Name] == 0 && available[card.Name] > 0 {
+			cards[card.Name] = 1
+		}
+	}
+	return cards
+}
+
+// OfferCompletion gives the player the completion discount for the next Conf.CompletionMinutes.
+func OfferCompletion(player Player, cards map[string]int) {
+	completionOffers.Lock()
+	defer completionOffers.Unlock()
+	completionOffers.offers[player] = completionOffer{cards, time.Now().Add(time.Duration(Conf.CompletionMinutes) * time.Minute)}
+	SetWTBRequest(player, cards)
+}
+
+func EndCompletion(player Player) {
+	completionOffers.Lock()
+	defer completionOffers.Unlock()
+	delete(completionOffers.offers, player)
+}
+
+// CompletionDiscount returns the gold taken off what we sell when it still contains the whole completion offer.
+func (s *State) CompletionDiscount(player Player, selling map[string]int) int {
+	completionOffers.Lock()
+	offer, ok := completionOffers.offers[player]
+	if ok && time.Now().After(offer.Expires) {
+		delete(completionOffers.offers, player)
+		ok = false
+	}
+	completionOffers.Unlock()
+
+	if !ok || len(offer.Cards) == 0 {
+		return 0
+	}
+	value := 0
+	for card, num := range offer.Cards {
+		if selling[card] < num {
+			return 0
+		}
+		value += s.DeterminePrice(card, num, false)
+	}
+	return value * Conf.CompletionDiscount / 100
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+)
+
+type CommandLimit struct {
+	CooldownSeconds int
+	Daily           int
+}
+
+type Config struct {
+	QueueMaxSkips     int
+	QueueRetrySeconds int
+
+	CommandLimits    map[string]CommandLimit
+	StrikesBeforeBan int
+	StrikeResetHours int
+	BanMinutes       int
+
+	Rooms []RoomProfile
+
+	Announcements []Announcement
+	QuietMinutes  int
+
+	PriceRules []PriceRule
+	Targets    []InventoryTarget
+
+	CompletionDiscount int
+	CompletionMinutes  int
+
+	ReservationMinutes  int
+	ReservationMaxCards int
+
+	MaxOrders      int
+	OrderMaxMisses int
+
+	AuctionIncrement    int
+	AuctionClaimMinutes int
+
+	RaffleMinutes       int
+	RaffleClaimMinutes  int
+	RaffleDonationValue int
+
+	SwapTolerance int
+
+	ConfirmTimeoutSeconds int
+
+	CardSelection string
+	Protected     Protection
+}
+
+var Conf = Config{
+	QueueMaxSkips:     3,
+	QueueRetrySeconds: 20,
+
+	CommandLimits: map[string]CommandLimit{
+		"!price": {CooldownSeconds: 5},
+		"!stock": {CooldownSeconds: 10},
+		"!wtb":   {CooldownSeconds: 3},
+		"!wts":   {CooldownSeconds: 3},
+		"!trade": {CooldownSeconds: 10, Daily: 20},
+	},
+	StrikesBeforeBan: 5,
+	StrikeResetHours: 24,
+	BanMinutes:       15,
+
+	Rooms: []RoomProfile{
+		{Name: "clockwork", Announce: true, Language: "en"},
+	},
+
+	Announcements: []Announcement{
+		{Kind: "demand", IntervalMinutes: 45},
+		{Kind: "acquired", IntervalMinutes: 20},
+		{Kind: "missing", IntervalMinutes: 60},
+		{Kind: "queue", IntervalMinutes: 30},
+		{Kind: "hint", IntervalMinutes: 90},
+		{Kind: "auction", IntervalMinutes: 15},
+	},
+	QuietMinutes: 15,
+
+	PriceRules: []PriceRule{
+		{Rarity: "common", Lower: 50, Upper: 150, Base: 100, Floor: 25},
+		{Rarity: "uncommon", Lower: 300, Upper: 600, Base: 600, Floor: 50},
+		{Rarity: "rare", Lower: 600, Upper: 1500, Base: 1200, Floor: 100},
+	},
+
+	CompletionDiscount: 10,
+	CompletionMinutes:  30,
+
+	ReservationMinutes:  15,
+	ReservationMaxCards: 10,
+
+	MaxOrders:      10,
+	OrderMaxMisses: 3,
+
+	AuctionIncrement:    5,
+	AuctionClaimMinutes: 30,
+
+	RaffleMinutes:       30,
+	RaffleClaimMinutes:  30,
+	RaffleDonationValue: 2000,
+
+	SwapTolerance: 5,
+
+	ConfirmTimeoutSeconds: 20,
+
+	CardSelection: "lowest_level",
+}
+
+func LoadConfig(filename string) {
+	if !loadJSON(filename, &Conf) {
+		log.Printf("LoadConfig: using defaults")
+	}
+}
+
+func loadJSON(filename string, v interface{}) bool {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		log.Printf("loadJSON: %s", err)
+		return false
+	}
+	err = json.Unmarshal(data, v)
+	if err != nil {
+		panic(err)
+	}
+	return true
+}
+
+func saveJSON(filename string, v interface{}) {
+	data, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		panic(err)
+	}
+	err = ioutil.WriteFile(filename, data, 0666)
+	if err != nil {
+		log.Printf("saveJSON: %s", err)
+	}
+}
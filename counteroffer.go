@@ -0,0 +1,125 @@
+package main
+
+import "sort"
+
+// Counteroffer is what we propose when the partner offers more than we can pay for: either the
+// part of their offer we'd still take, or cards of ours to add so the trade balances.
+type Counteroffer struct {
+	Keep   map[string]int
+	Remove map[string]int
+	Add    map[string]int
+
+	KeepGold int // what we pay for the kept cards, or
+	KeepOwed int // what the partner pays us when the kept cards are worth less than ours
+	AddGold  int
+}
+
+const counterofferUnits = 5000
+
+// bestFit picks how many copies of each card to take so the total price is as high as possible without
+// exceeding capacity. prices[card][k-1] is the price of k copies. Prices are rounded up to units of
+// capacity/counterofferUnits gold, with a unit of slack per card so rounding alone can't push out a
+// combination that fits. The result may still be a little below the optimum but never above capacity.
+func bestFit(prices map[string][]int, capacity int) (counts map[string]int, total int) {
+	counts = make(map[string]int)
+	if capacity <= 0 {
+		return counts, 0
+	}
+	unit := capacity/counterofferUnits + 1
+	slots := capacity/unit + len(prices)
+
+	cards := make([]string, 0, len(prices))
+	for card := range prices {
+		cards = append(cards, card)
+	}
+	sort.Strings(cards)
+	// best[c] is the highest price reachable with cost c, choice[i][c] how many of card i it takes.
+	best := make([]int, slots+1)
+	for c := range best {
+		best[c] = -1
+	}
+	best[0] = 0
+	choice := make([][]int, len(cards))
+	for i, card := range cards {
+		next := append([]int(nil), best...)
+		choice[i] = make([]int, slots+1)
+		for k, price := range prices[card] {
+			cost := (price + unit - 1) / unit
+			for c := slots; c >= cost; c-- {
+				if best[c-cost] >= 0 && best[c-cost]+price > next[c] {
+					next[c] = best[c-cost] + price
+					choice[i][c] = k + 1
+				}
+			}
+		}
+		best = next
+	}
+
+	c := 0
+	for i := range best {
+		if best[i] > best[c] && best[i] <= capacity {
+			c = i
+		}
+	}
+	total = best[c]
+	for i := len(cards) - 1; i >= 0; i-- {
+		if k := choice[i][c]; k > 0 {
+			counts[cards[i]] = k
+			c -= (prices[cards[i]][k-1] + unit - 1) / unit
+		}
+	}
+	return counts, total
+}
+
+// keep picks which of their cards we can take within the budget next to our cards worth mine.
+func (offer *Counteroffer) keep(their map[string]int, prices map[string][]int, budget, mine int) bool {
+	keep, value := bestFit(prices, budget+mine)
+	if value <= 0 {
+		return false
+	}
+	offer.Keep = keep
+	offer.Remove = make(map[string]int)
+	for card, num := range their {
+		if num > keep[card] {
+			offer.Remove[card] = num - keep[card]
+		}
+	}
+	if value >= mine {
+		offer.KeepGold = value - mine
+	} else {
+		offer.KeepOwed = mine - value
+	}
+	return true
+}
+
+// Counteroffer proposes how to bring a trade we can't afford within GoldForTrade.
+func (s *State) Counteroffer(partner Player, ts TradeStatus) (offer Counteroffer, ok bool) {
+	budget := GoldForTrade()
+	excess := ts.Their.Value - ts.My.Value
+	if excess <= budget {
+		return offer, false
+	}
+
+	prices := make(map[string][]int)
+	for card, num := range ts.Their.Cards {
+		for k := 1; k <= num; k++ {
+			prices[card] = append(prices[card], s.DeterminePrice(card, k, true))
+		}
+	}
+	ok = offer.keep(ts.Their.Cards, prices, budget, ts.My.Value)
+
+	owned := OwnedCards(partner)
+	available := Reservations.Available(partner)
+	prices = make(map[string][]int)
+	for card, num := range available {
+		if num > ts.My.Cards[card] && owned[card] == 0 && ts.My.Cards[card] == 0 {
+			prices[card] = []int{s.SellPrice(partner, card, 1)}
+		}
+	}
+	if add, value := bestFit(prices, excess); len(add) > 0 && excess-value <= budget {
+		offer.Add = add
+		offer.AddGold = excess - value
+		ok = true
+	}
+	return offer, ok
+}
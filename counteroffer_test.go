@@ -0,0 +1,81 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBestFit(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		prices   map[string][]int
+		capacity int
+		counts   map[string]int
+		total    int
+	}{
+		{"no capacity", map[string][]int{"Wolf": {100}}, 0, map[string]int{}, 0},
+		{"nothing fits", map[string][]int{"Wolf": {500}}, 400, map[string]int{}, 0},
+		{"copies of one card", map[string][]int{"Wolf": {100, 190, 270}}, 200, map[string]int{"Wolf": 2}, 190},
+		{"exact fit", map[string][]int{"Wolf": {300}, "Bear": {250}, "Bolt": {200}}, 450, map[string]int{"Bear": 1, "Bolt": 1}, 450},
+		{"fewer copies and another card", map[string][]int{"Wolf": {100, 200, 300}, "Bear": {150}}, 350, map[string]int{"Wolf": 2, "Bear": 1}, 350},
+		{"everything fits", map[string][]int{"Wolf": {100, 200}, "Bear": {150}}, 1000, map[string]int{"Wolf": 2, "Bear": 1}, 350},
+	} {
+		counts, total := bestFit(test.prices, test.capacity)
+		if !reflect.DeepEqual(counts, test.counts) || total != test.total {
+			t.Errorf("%s: bestFit = %v, %d, want %v, %d", test.name, counts, total, test.counts, test.total)
+		}
+	}
+}
+
+func TestBestFitRounding(t *testing.T) {
+	// With capacities this large prices are rounded up to 200g units, which must neither push out
+	// two cards that just fit nor let the total exceed capacity.
+	prices := map[string][]int{"Wolf": {500000}, "Bear": {499999}, "Bolt": {1000, 2000, 3000}}
+	for _, test := range []struct {
+		capacity int
+		total    int
+	}{
+		{1000000, 999999},
+		{999999, 999999},
+		{999998, 503000},
+		{503000, 503000},
+		{2999, 2000},
+	} {
+		capacity := test.capacity
+		counts, total := bestFit(prices, capacity)
+		if total != test.total {
+			t.Errorf("bestFit(%d) = %d, want %d", capacity, total, test.total)
+		}
+		sum := 0
+		for card, k := range counts {
+			sum += prices[card][k-1]
+		}
+		if sum != total {
+			t.Errorf("bestFit(%d) counts %v add up to %d, not %d", capacity, counts, sum, total)
+		}
+	}
+}
+
+func TestCounterofferKeep(t *testing.T) {
+	their := map[string]int{"Dragon": 1, "Wolf": 1}
+	prices := map[string][]int{"Dragon": {700}, "Wolf": {50}}
+	for _, test := range []struct {
+		name           string
+		budget, mine   int
+		keep           map[string]int
+		keepGold, owed int
+	}{
+		{"we pay the difference", 220, 500, map[string]int{"Dragon": 1}, 200, 0},
+		{"they pay the difference", 100, 500, map[string]int{"Wolf": 1}, 0, 450},
+	} {
+		var offer Counteroffer
+		if !offer.keep(their, prices, test.budget, test.mine) {
+			t.Errorf("%s: no offer", test.name)
+			continue
+		}
+		if !reflect.DeepEqual(offer.Keep, test.keep) || offer.KeepGold != test.keepGold || offer.KeepOwed != test.owed {
+			t.Errorf("%s: keep %v for %dg, owed %dg, want %v for %dg, owed %dg",
+				test.name, offer.Keep, offer.KeepGold, offer.KeepOwed, test.keep, test.keepGold, test.owed)
+		}
+	}
+}
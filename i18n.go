@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+type Vars map[string]interface{}
+
+type Catalog map[string]string
+
+var Catalogs = map[string]Catalog{
+	"en": catalogEN,
+	"de": catalogDE,
+}
+
+var catalogFuncs = template.FuncMap{
+	"join": func(list interface{}, sep string) string {
+		switch list := list.(type) {
+		case []string:
+			return strings.Join(list, sep)
+		case []Player:
+			names := make([]string, len(list))
+			for i, player := range list {
+				names[i] = string(player)
+			}
+			return strings.Join(names, sep)
+		}
+		return ""
+	},
+}
+
+var templates = struct {
+	sync.Mutex
+	parsed map[string]*template.Template
+}{parsed: make(map[string]*template.Template)}
+
+func compileTemplate(name, text string) (*template.Template, error) {
+	templates.Lock()
+	defer templates.Unlock()
+
+	t, ok := templates.parsed[name]
+	if !ok {
+		var err error
+		t, err = template.New(name).Funcs(catalogFuncs).Parse(text)
+		if err != nil {
+			return nil, err
+		}
+		templates.parsed[name] = t
+	}
+	return t, nil
+}
+
+func renderTemplate(name, text string, vars interface{}) string {
+	t, err := compileTemplate(name, text)
+	if err != nil {
+		log.Printf("renderTemplate: %s", err)
+		return ""
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, vars); err != nil {
+		log.Printf("renderTemplate: %s", err)
+		return ""
+	}
+	return b.String()
+}
+
+// Tr renders the message with the given key from the catalog of lang, falling back to English.
+func Tr(lang, key string, vars interface{}) string {
+	text, ok := Catalogs[lang][key]
+	if !ok {
+		lang = "en"
+		text, ok = Catalogs[lang][key]
+	}
+	if !ok {
+		log.Printf("Tr: missing message '%s'", key)
+		return key
+	}
+	return renderTemplate(lang+"/"+key, text, vars)
+}
+
+func LanguageNames() []string {
+	names := make([]string, 0, len(Catalogs))
+	for lang := range Catalogs {
+		names = append(names, lang)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type LanguageStore struct {
+	sync.Mutex
+	filename string
+	players  map[Player]string
+}
+
+var Languages *LanguageStore
+
+func LoadLanguages(filename string) *LanguageStore {
+	l := &LanguageStore{filename: filename, players: make(map[Player]string)}
+	loadJSON(filename, &l.players)
+	return l
+}
+
+func (l *LanguageStore) Get(player Player) string {
+	l.Lock()
+	defer l.Unlock()
+	return l.players[player]
+}
+
+func (l *LanguageStore) Set(player Player, lang string) {
+	l.Lock()
+	defer l.Unlock()
+	l.players[player] = lang
+	saveJSON(l.filename, l.players)
+}
+
+// LanguageFor picks the player's preferred language, or the language of the room they're talking in.
+func LanguageFor(player Player, room Channel) string {
+	if lang := Languages.Get(player); lang != "" {
+		return lang
+	}
+	if profile, ok := RoomProfileFor(room); ok && profile.Language != "" {
+		return profile.Language
+	}
+	return "en"
+}
+
+func (s *State) WhisperTr(player Player, key string, vars interface{}) {
+	s.Whisper(player, Tr(LanguageFor(player, "WHISPER"), key, vars))
+}
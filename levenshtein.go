@@ -1,36 +1,43 @@
 package main
 
-import "math"
+// DamerauLevenshtein is the edit distance between a and b, counting insertions, deletions, substitutions
+// and swaps of two adjacent characters as one edit each. It's the unrestricted variant, so characters
+// may be edited again after a swap.
+func DamerauLevenshtein(a, b string) int {
+	w := len(b) + 2
+	d := make([]int, (len(a)+2)*w)
+	maxDist := len(a) + len(b)
+	var lastRow [256]int
 
-func Levenshtein(a, b string) int {
-	var cost int
-	d := make([][]int, len(a)+1)
-	for i := 0; i < len(d); i++ {
-		d[i] = make([]int, len(b)+1)
+	d[0] = maxDist
+	for i := 0; i <= len(a); i++ {
+		d[(i+1)*w] = maxDist
+		d[(i+1)*w+1] = i
 	}
-	var min1, min2, min3 int
-
-	for i := 0; i < len(d); i++ {
-		d[i][0] = i
+	for j := 0; j <= len(b); j++ {
+		d[j+1] = maxDist
+		d[w+j+1] = j
 	}
 
-	for i := 0; i < len(d[0]); i++ {
-		d[0][i] = i
-	}
-	for i := 1; i < len(d); i++ {
-		for j := 1; j < len(d[0]); j++ {
+	for i := 1; i <= len(a); i++ {
+		lastCol := 0
+		for j := 1; j <= len(b); j++ {
+			k := lastRow[b[j-1]]
+			l := lastCol
+			cost := 1
 			if a[i-1] == b[j-1] {
 				cost = 0
-			} else {
-				cost = 1
+				lastCol = j
 			}
-
-			min1 = d[i-1][j] + 1
-			min2 = d[i][j-1] + 1
-			min3 = d[i-1][j-1] + cost
-			d[i][j] = int(math.Min(math.Min(float64(min1), float64(min2)), float64(min3)))
+			d[(i+1)*w+j+1] = min(
+				d[i*w+j]+cost,
+				d[(i+1)*w+j]+1,
+				d[i*w+j+1]+1,
+				d[k*w+l]+(i-k-1)+1+(j-l-1),
+			)
 		}
+		lastRow[a[i-1]] = i
 	}
 
-	return d[len(a)][len(b)]
+	return d[(len(a)+1)*w+len(b)+1]
 }
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const libraryMaxAge = 10 * time.Minute
+
+var libraries = struct {
+	sync.Mutex
+	fetched map[Player]time.Time
+	waiting map[Player][]chan bool
+}{
+	fetched: make(map[Player]time.Time),
+	waiting: make(map[Player][]chan bool),
+}
+
+func libraryArrived(player Player) {
+	libraries.Lock()
+	defer libraries.Unlock()
+	libraries.fetched[player] = time.Now()
+	for _, ch := range libraries.waiting[player] {
+		close(ch)
+	}
+	delete(libraries.waiting, player)
+}
+
+func (s *State) RequestLibrary(player Player) bool {
+	id, ok := PlayerIds[player]
+	if !ok {
+		return false
+	}
+	s.SendRequest(Request{"msg": "LibraryView", "profileId": id})
+	return true
+}
+
+// FetchLibrary makes sure Libraries and Stocks hold a recent copy of the player's collection, asking
+// the server for it if necessary.
+func (s *State) FetchLibrary(player Player, timeout time.Duration) bool {
+	libraries.Lock()
+	if time.Since(libraries.fetched[player]) < libraryMaxAge {
+		libraries.Unlock()
+		return true
+	}
+	ch := make(chan bool)
+	libraries.waiting[player] = append(libraries.waiting[player], ch)
+	libraries.Unlock()
+
+	if !s.RequestLibrary(player) {
+		stopWaiting(player, ch)
+		return false
+	}
+	select {
+	case <-ch:
+		return true
+	case <-time.After(timeout):
+		stopWaiting(player, ch)
+		return false
+	}
+}
+
+func stopWaiting(player Player, ch chan bool) {
+	libraries.Lock()
+	defer libraries.Unlock()
+	waiting := libraries.waiting[player]
+	for i, c := range waiting {
+		if c == ch {
+			waiting = append(waiting[:i], waiting[i+1:]...)
+			break
+		}
+	}
+	if len(waiting) == 0 {
+		delete(libraries.waiting, player)
+	} else {
+		libraries.waiting[player] = waiting
+	}
+}
+
+type LibraryCard struct {
+	Id       int
+	Name     string
+	Tradable bool
+	Level    int
+}
+
+// LibraryIndex looks up the cards of a player's library by id and their tradable copies by type.
+type LibraryIndex struct {
+	byId     map[int]*LibraryCard
+	tradable map[string][]int // in the order we offer them, see offerOrder
+	owned    map[string]int
+
+	protected      map[int]bool // only for our own library
+	protectedCount map[string]int
+}
+
+var libraryIndexes = struct {
+	sync.RWMutex
+	players map[Player]*LibraryIndex
+}{players: make(map[Player]*LibraryIndex)}
+
+var emptyLibrary = &LibraryIndex{}
+
+func IndexLibrary(player Player, v MLibraryView) {
+	index := &LibraryIndex{
+		byId:     make(map[int]*LibraryCard, len(v.Cards)),
+		tradable: make(map[string][]int),
+		owned:    make(map[string]int),
+	}
+	for _, card := range v.Cards {
+		name := CardTypes[CardId(card.TypeId)]
+		index.byId[card.Id] = &LibraryCard{card.Id, name, card.Tradable, card.Level}
+		index.owned[name]++
+		if card.Tradable {
+			index.tradable[name] = append(index.tradable[name], card.Id)
+		}
+	}
+	for _, ids := range index.tradable {
+		sort.Slice(ids, func(i, j int) bool { return offerOrder(index.byId[ids[i]], index.byId[ids[j]]) })
+	}
+	index.protectedCount = make(map[string]int)
+	if player == Bot {
+		index.protected = protectedIds(index)
+		for id := range index.protected {
+			index.protectedCount[index.byId[id].Name]++
+		}
+	}
+
+	libraryIndexes.Lock()
+	libraryIndexes.players[player] = index
+	libraryIndexes.Unlock()
+	if player == Bot {
+		Reservations.Refresh(index)
+	}
+}
+
+// offerOrder says which of two copies of a card we'd rather give away according to Conf.CardSelection:
+// "newest" prefers the highest id, "lowest_level" the lowest level and then the lowest id.
+func offerOrder(a, b *LibraryCard) bool {
+	if Conf.CardSelection == "newest" {
+		return a.Id > b.Id
+	}
+	if a.Level != b.Level {
+		return a.Level < b.Level
+	}
+	return a.Id < b.Id
+}
+
+func LibraryOf(player Player) *LibraryIndex {
+	libraryIndexes.RLock()
+	defer libraryIndexes.RUnlock()
+	if index, ok := libraryIndexes.players[player]; ok {
+		return index
+	}
+	return emptyLibrary
+}
+
+func (l *LibraryIndex) Card(id int) (*LibraryCard, bool) {
+	card, ok := l.byId[id]
+	return card, ok
+}
+
+// Tradable returns the ids of the tradable copies of the card. The slice must not be modified.
+func (l *LibraryIndex) Tradable(name string) []int {
+	return l.tradable[name]
+}
+
+func (l *LibraryIndex) TradableTypes() []string {
+	names := make([]string, 0, len(l.tradable))
+	for name := range l.tradable {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Protected returns the ids of the copies we keep. The map must not be modified.
+func (l *LibraryIndex) Protected() map[int]bool {
+	return l.protected
+}
+
+// ProtectedCount returns how many tradable copies of the card we keep.
+func (l *LibraryIndex) ProtectedCount(name string) int {
+	return l.protectedCount[name]
+}
+
+// Count counts the card types of the given ids, skipping ids that aren't in the library.
+func (l *LibraryIndex) Count(ids []int) map[string]int {
+	count := make(map[string]int)
+	for _, id := range ids {
+		if card, ok := l.byId[id]; ok {
+			count[card.Name]++
+		}
+	}
+	return count
+}
+
+// OwnedCards counts all copies of each card type in the player's library, tradable or not.
+func OwnedCards(player Player) map[string]int {
+	owned := make(map[string]int)
+	for name, num := range LibraryOf(player).owned {
+		owned[name] = num
+	}
+	return owned
+}
+
+func FindPlayer(name string) (Player, bool) {
+	for player := range PlayerIds {
+		if strings.EqualFold(string(player), name) {
+			return player, true
+		}
+	}
+	return "", false
+}
+
+// WantedFrom lists how many copies of each card the player could sell to us while we'd still pay more
+// than the minimum value for them.
+func (s *State) WantedFrom(player Player) map[string]int {
+	wanted := make(map[string]int)
+	for card, num := range Stocks[player] {
+		if limit := BuyLimit(card); limit >= 0 && num > limit {
+			num = limit
+		}
+		for n := 1; n <= num; n++ {
+			if s.DeterminePrice(card, n, true)-s.DeterminePrice(card, n-1, true) <= MinimumValue(card) {
+				break
+			}
+			wanted[card] = n
+		}
+	}
+	return wanted
+}
+
+func sortedNames(cards map[string]int) []string {
+	names := make([]string, 0, len(cards))
+	for card := range cards {
+		names = append(names, card)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func cardCounts(cards map[string]int) []string {
+	list := make([]string, 0, len(cards))
+	for _, card := range sortedNames(cards) {
+		if num := cards[card]; num != 1 {
+			list = append(list, fmt.Sprintf("%dx %s", num, card))
+		} else {
+			list = append(list, card)
+		}
+	}
+	return list
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestOfferOrder(t *testing.T) {
+	defer func(selection string) { Conf.CardSelection = selection }(Conf.CardSelection)
+	cards := []*LibraryCard{
+		{Id: 7, Level: 0},
+		{Id: 3, Level: 1},
+		{Id: 9, Level: 0},
+		{Id: 5, Level: 2},
+		{Id: 4, Level: 0},
+	}
+
+	for _, test := range []struct {
+		selection string
+		want      []int
+	}{
+		{"lowest_level", []int{4, 7, 9, 3, 5}},
+		{"", []int{4, 7, 9, 3, 5}},
+		{"newest", []int{9, 7, 5, 4, 3}},
+	} {
+		Conf.CardSelection = test.selection
+		sorted := append([]*LibraryCard(nil), cards...)
+		sort.Slice(sorted, func(i, j int) bool { return offerOrder(sorted[i], sorted[j]) })
+		got := make([]int, len(sorted))
+		for i, card := range sorted {
+			got[i] = card.Id
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("offer order for %q = %v, want %v", test.selection, got, test.want)
+		}
+	}
+}
@@ -1,4 +1,3 @@
-// TODO: !add [scroll] [quantity]
 // TODO: reduce price for multiple scrolls
 // TODO: ask for scrolls prices outside trade
 
@@ -9,18 +8,13 @@ import (
 	"io"
 	"io/ioutil"
 	"log"
-	"math/rand"
 	"os"
-	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 )
 
-var reNumbers = regexp.MustCompile(`x?(\d+)x?`)
-var reInvalidChars = regexp.MustCompile("[^a-z'0-9 ]")
-
-var WTBrequests = make(map[Player]map[string]int)
 var Bot Player
 
 func main() {
@@ -31,11 +25,20 @@ func main() {
 	log.SetOutput(f)
 	// log.SetOutput(ioutil.Discard)
 
-	// startBot("I live again!")
+	LoadConfig("config.json")
+	ACL = LoadACL("acl.json")
+	Languages = LoadLanguages("languages.json")
+	Aliases = LoadAliases("aliases.json")
+	Notifications = LoadNotifications("notify.json")
+	Orders = LoadOrders("orders.json")
+	Auctions = LoadAuctions("auctions.json")
+	Raffles = LoadRaffles("raffles.json")
+
+	// startBot("bot.revived")
 	startBot("")
 }
 
-func startBot(helloMessage string) {
+func startBot(helloKey string) {
 	login, err := ioutil.ReadFile("login.txt")
 	if err != nil {
 		panic(err)
@@ -46,53 +49,60 @@ func startBot(helloMessage string) {
 	}
 
 	s, chAlive := Connect(split[0], split[1])
-	s.JoinRoom("clockwork")
-	if helloMessage != "" {
-		s.Say("clockwork", helloMessage)
+	s.JoinRooms()
+	if helloKey != "" {
+		s.Announce(helloKey, nil)
 	}
 
 	upSince := time.Now()
 
 	chKillThread := make(chan bool, 1)
 
-	go func() {
-		queue := make([]Player, 0)
-
-		chReadyToTrade := make(chan bool, 100)
-		currentlyTrading := false
+	Queue = NewTradeQueue()
 
+	go func() {
 		messages := s.Listen()
 		defer s.Shut(messages)
+		announceTicker := time.Tick(time.Minute)
 
 		for {
 			select {
 			case <-chKillThread:
 				return
 
-			case <-chReadyToTrade:
-				if len(queue) == 0 {
-					s.Say("clockwork", "Finished trading.")
-					currentlyTrading = false
+			case <-announceTicker:
+				s.RunAuctions()
+				s.RunRaffles()
+				s.RunAnnouncements()
+
+			case <-Queue.Ready:
+				partner, returning, dropped, ok := Queue.Next(Presence.IsPresent)
+				for _, player := range dropped {
+					s.WhisperTr(player, "queue.dropped", nil)
+				}
+				if !ok {
+					if len(Queue.Waiting()) == 0 {
+						s.Announce("queue.finished", nil)
+					} else {
+						Queue.Retry()
+					}
 				} else {
-					currentlyTrading = true
+					if returning {
+						s.WhisperTr(partner, "queue.your_turn", nil)
+					}
 
 					go func() {
-						waiting := make([]string, len(queue)-1)
-						for i, name := range queue[1:] {
-							waiting[i] = string(name)
-						}
-						if len(waiting) > 0 {
-							s.Say("clockwork", fmt.Sprintf("Now trading with [%s] < %s", queue[0], strings.Join(waiting, " < ")))
-						} else {
-							s.Say("clockwork", fmt.Sprintf("Now trading with [%s].", queue[0]))
-						}
+						s.Announce("queue.now_trading", Vars{"Partner": partner, "Waiting": Queue.Waiting()})
 
 						stockBefore := Stocks[Bot]
 						if stockBefore == nil {
 							stockBefore = make(map[string]int)
 						}
 
-						ts := s.Trade(queue[0])
+						ts := s.Trade(partner)
+						if ts.TimedOut {
+							s.punish(partner, "timeout", "let a trade time out")
+						}
 
 						aquired := make([]string, 0)
 						lost := make([]string, 0)
@@ -109,51 +119,130 @@ func startBot(helloMessage string) {
 							stockBefore[card] = stockBefore[card] - num
 						}
 						if len(aquired) > 0 {
-							s.Say("clockwork", fmt.Sprintf("I've just aquired %s.", strings.Join(aquired, ", ")))
+							NoteAcquired(aquired)
+							s.Announce("trade.acquired", Vars{"Cards": aquired})
 						}
 						if len(lost) > 0 {
-							s.Say("clockwork", fmt.Sprintf("I've just sold my last %s.", strings.Join(lost, ", ")))
+							s.Announce("trade.sold_last", Vars{"Cards": lost})
 						}
 
-						queue = queue[1:]
-						chReadyToTrade <- true
+						s.CheckNotifications()
+						s.CheckOrders()
+						Queue.Ready <- true
 					}()
 				}
 
 			case m := <-messages:
-				if m.From == "redefiance" && strings.HasPrefix(m.Text, "!say ") {
-					s.Say("clockwork", strings.TrimPrefix(m.Text, "!say "))
+				Presence.Seen(m.From)
+				if m.Channel != "WHISPER" && m.From != Bot && m.From != "Scrolls" {
+					RoomActivity[m.Channel] = time.Now()
+				}
+
+				if ACL.IsAdmin(m.From) && strings.HasPrefix(m.Text, "!say ") {
+					s.announceEach(func(string) string {
+						return strings.TrimPrefix(m.Text, "!say ")
+					})
 				}
 
 				forceWhisper := false
 				replyMsg := ""
-				command := strings.ToLower(m.Text)
+				text := m.Text
+				lang := LanguageFor(m.From, m.Channel)
 
-				// if m.From != "redefiance" {
-				if m.From == "Great_Marcoosai" {
-					command = "" // banned!
+				if strings.HasPrefix(strings.ToLower(text), "wt") {
+					text = "!" + text
+				}
 
+				if m.Channel == "WHISPER" && !strings.HasPrefix(text, "!") {
+					text = "!" + text
 				}
+				command := strings.ToLower(text)
 
-				if strings.HasPrefix(command, "wt") {
-					command = strings.Replace(command, "wt", "!wt", 1)
+				if profile, ok := RoomProfileFor(m.Channel); ok && !profile.Allows(command) {
+					command = ""
 				}
 
-				if m.Channel == "WHISPER" && !strings.HasPrefix(command, "!") {
-					command = "!" + command
+				if banned, _ := ACL.IsBanned(m.From); banned {
+					command = ""
+				} else if m.Channel != TradeRoom && m.From != Bot {
+					if ok, reason, vars := Limiter.Allow(m.From, command); !ok {
+						if reason != "" {
+							s.WhisperTr(m.From, reason, vars)
+							s.punish(m.From, "limit", "hit the limit for "+commandName(command))
+						}
+						command = ""
+					}
 				}
 
-				if command == "!wts" || command == "!wtb" {
-					replyMsg = "You need to add a list of cards to this command, seperated by commata. Multipliers like '2x' are allowed."
+				if command == "!wtb" {
+					replyMsg = Tr(lang, "list.needed", nil)
 					forceWhisper = true
 				}
 
+				if command == "!wts" && m.Channel != TradeRoom {
+					go func(player Player) {
+						if !s.FetchLibrary(player, 5*time.Second) {
+							s.WhisperTr(player, "library.unavailable", nil)
+							return
+						}
+						const maxQuotes = 10
+						wanted := s.WantedFrom(player)
+						prices := make([]CardPrice, 0, len(wanted))
+						for card, num := range wanted {
+							prices = append(prices, CardPrice{card, s.DeterminePrice(card, num, true)})
+						}
+						sort.Slice(prices, func(i, j int) bool {
+							if prices[i].Price != prices[j].Price {
+								return prices[i].Price > prices[j].Price
+							}
+							return prices[i].Name < prices[j].Name
+						})
+						if len(prices) > maxQuotes {
+							prices = prices[:maxQuotes]
+						}
+						if len(prices) == 0 {
+							s.WhisperTr(player, "wts.nothing_wanted", nil)
+							return
+						}
+
+						quotes := make([]string, len(prices))
+						goldSum := 0
+						for i, p := range prices {
+							quotes[i] = fmt.Sprintf("%s %d", p.Name, p.Price)
+							if num := wanted[p.Name]; num != 1 {
+								quotes[i] = fmt.Sprintf("%dx %s", num, quotes[i])
+							}
+							goldSum += p.Price
+						}
+						s.WhisperTr(player, "wts.quote", Vars{
+							"Quotes":   quotes,
+							"Sum":      goldSum,
+							"TooPoor":  goldSum > GoldForTrade(),
+							"Budget":   GoldForTrade(),
+							"Problems": "",
+						})
+					}(m.From)
+				}
+
 				if strings.HasPrefix(command, "!wts ") && m.Channel != TradeRoom {
-					cards, failedWords := parseCardList(strings.TrimPrefix(command, "!wts "))
-					if len(cards) > 0 {
+					cards, errors := parseCardList(strings.TrimPrefix(command, "!wts "), Stocks[m.From])
+					problems := parseProblems(lang, errors)
+					if len(cards) == 0 && len(errors) > 0 {
+						replyMsg = problems
+						forceWhisper = true
+					} else if len(cards) > 0 {
 						words := make([]string, 0, len(cards))
+						refused := make([]string, 0)
+						capped := make([]string, 0)
 						goldSum := 0
 						for card, num := range cards {
+							if limit := BuyLimit(card); limit == 0 {
+								refused = append(refused, card)
+								continue
+							} else if limit > 0 && num > limit {
+								capped = append(capped, fmt.Sprintf("%dx %s", limit, card))
+								num = limit
+							}
 							gold := s.DeterminePrice(card, num, true)
 							numStr := ""
 							if num != 1 {
@@ -163,27 +252,28 @@ func startBot(helloMessage string) {
 							goldSum += gold
 						}
 
-						s1, s2, s3, s4 := "will", "", "", ""
-						if goldSum > GoldForTrade() {
-							s1 = "would"
-							s3 = fmt.Sprintf(" I currently only have %dg.", GoldForTrade())
-						}
-						if len(words) > 1 {
-							s2 = fmt.Sprintf(" That sums up to %dg.", goldSum)
-						}
-						if len(failedWords) > 0 {
-							s4 = fmt.Sprintf(" I don't know what '%s' is.", strings.Join(failedWords, ", "))
-						}
-
-						replyMsg = fmt.Sprintf("I %s pay %s.%s%s%s", s1, strings.Join(words, ", "), s2, s3, s4)
+						replyMsg = Tr(lang, "wts.quote", Vars{
+							"Quotes":   words,
+							"Sum":      goldSum,
+							"TooPoor":  goldSum > GoldForTrade(),
+							"Budget":   GoldForTrade(),
+							"Refused":  refused,
+							"Capped":   capped,
+							"Problems": problems,
+						})
 						forceWhisper = true
 					}
 				}
 
 				if strings.HasPrefix(command, "!wtb ") && m.Channel != TradeRoom {
-					cards, failedWords := parseCardList(strings.TrimPrefix(command, "!wtb "))
-					WTBrequests[m.From] = cards
-					if len(cards) > 0 {
+					available := Reservations.Available(m.From)
+					cards, errors := parseCardList(strings.TrimPrefix(command, "!wtb "), available)
+					problems := parseProblems(lang, errors)
+					SetWTBRequest(m.From, cards)
+					if len(cards) == 0 && len(errors) > 0 {
+						replyMsg = problems
+						forceWhisper = true
+					} else if len(cards) > 0 {
 						words := make([]string, 0, len(cards))
 						numItems := 0
 						goldSum := 0
@@ -191,7 +281,7 @@ func startBot(helloMessage string) {
 						for card, num := range cards {
 							forceNumStr := false
 							numItems += num
-							if stocked := Stocks[Bot][card]; num > stocked {
+							if stocked := available[card]; num > stocked {
 								num = stocked
 								hasAll = false
 								forceNumStr = true
@@ -209,59 +299,132 @@ func startBot(helloMessage string) {
 							goldSum += gold
 						}
 
-						s1, s2, s3 := "", "", ""
-						if !hasAll {
-							s1 = " That's all I have."
-						}
-						if len(words) > 1 {
-							s2 = fmt.Sprintf(" That sums up to %dg.", goldSum)
-						}
-						if len(failedWords) > 0 {
-							s3 = fmt.Sprintf(" I don't know what '%s' is.", strings.Join(failedWords, ", "))
-						}
-
 						if goldSum == 0 {
+							card := ""
 							if numItems == 1 {
-								replyMsg = "I don't have "
-								for card, _ := range cards {
-									replyMsg += card
+								for card = range cards {
 									break
 								}
-								replyMsg += " stocked."
-							} else {
-								replyMsg = "I don't have anything on that list stocked."
 							}
-							replyMsg += s3
+							replyMsg = Tr(lang, "wtb.none", Vars{"Card": card, "Problems": problems})
 						} else {
-							replyMsg = fmt.Sprintf("I want to have %s.%s%s%s", strings.Join(words, ", "), s1, s2, s3)
+							replyMsg = Tr(lang, "wtb.quote", Vars{
+								"Quotes":   words,
+								"Partial":  !hasAll,
+								"Sum":      goldSum,
+								"Problems": problems,
+							})
 						}
 						forceWhisper = true
 					}
 				}
 
 				if strings.HasPrefix(command, "!price ") || strings.HasPrefix(command, "!stock ") {
-					cardName := matchCardName(strings.TrimPrefix(strings.TrimPrefix(command, "!stock "), "!price "))
-					stocked, ok := Stocks[Bot][cardName]
-					if !ok {
-						replyMsg = "There is no card named '" + cardName + "'"
+					word := strings.TrimPrefix(strings.TrimPrefix(command, "!stock "), "!price ")
+					cardName, options := resolveCardName(word)
+					_, ok := Stocks[Bot][cardName]
+					if len(options) > 0 {
+						replyMsg = Tr(lang, "card.ambiguous", ParseError{Word: word, Options: options})
+					} else if !ok {
+						if cardName == "" {
+							cardName = word
+						}
+						replyMsg = Tr(lang, "price.unknown", Vars{"Card": cardName})
 					} else {
-						if stocked == 0 {
-							price := s.DeterminePrice(cardName, 1, true)
-							replyMsg = cardName + " is out of stock. "
-							if price > GoldForTrade() {
-								replyMsg += fmt.Sprintf("I would buy for %dg, but I don't have that much (base value %dg).", price, BaseValue(cardName))
-							} else {
-								replyMsg += fmt.Sprintf("I'm buying for %dg (base value %dg).", price, BaseValue(cardName))
-							}
-
+						// Protected and reserved copies aren't for sale, so they don't count as stocked here.
+						available := Reservations.Available(m.From)[cardName]
+						vars := Vars{
+							"Card":    cardName,
+							"Buy":     s.DeterminePrice(cardName, 1, true),
+							"Base":    BaseValue(cardName),
+							"Stocked": available,
+						}
+						if available <= 0 {
+							vars["TooPoor"] = vars["Buy"].(int) > GoldForTrade()
+							replyMsg = Tr(lang, "price.out_of_stock", vars)
 						} else {
-							replyMsg = fmt.Sprintf("I'm buying %s for %dg and selling for %dg (base value %dg, %d stocked).", cardName,
-								s.DeterminePrice(cardName, 1, true), s.DeterminePrice(cardName, 1, false), BaseValue(cardName), stocked)
+							vars["Sell"] = s.DeterminePrice(cardName, 1, false)
+							replyMsg = Tr(lang, "price.quote", vars)
 						}
 					}
+				}
 
-					if rand.Float64() > 0.95 {
-						replyMsg += " By the way, you can whisper me with 'wtb/wts [list of cards]' to easily check prices and availability for all cards you're interested in."
+				if strings.HasPrefix(command, "!card ") {
+					word := strings.TrimPrefix(command, "!card ")
+					cardName, options := resolveCardName(word)
+					if card, ok := Cards.ByName(cardName); ok {
+						replyMsg = Tr(lang, "card.info", card)
+					} else if len(options) > 0 {
+						replyMsg = Tr(lang, "card.ambiguous", ParseError{Word: word, Options: options})
+					} else {
+						replyMsg = Tr(lang, "price.unknown", Vars{"Card": word})
+					}
+					forceWhisper = true
+				}
+
+				if command == "!search" || strings.HasPrefix(command, "!search ") {
+					const maxNames = 15
+					f, err := ParseCardFilter(strings.TrimPrefix(command, "!search"))
+					if err != nil || f.IsEmpty() {
+						word := ""
+						if err != nil {
+							word = err.Error()
+						}
+						replyMsg = Tr(lang, "search.invalid", Vars{"Word": word})
+					} else if found := Cards.Filter(f); len(found) == 0 {
+						replyMsg = Tr(lang, "search.none", nil)
+					} else {
+						names := make([]string, 0, maxNames)
+						for _, card := range found {
+							if len(names) == maxNames {
+								break
+							}
+							names = append(names, card.Name)
+						}
+						replyMsg = Tr(lang, "search.result", Vars{"Count": len(found), "Names": names, "More": len(found) - len(names)})
+					}
+					forceWhisper = true
+				}
+
+				if command == "!complete" || strings.HasPrefix(command, "!complete ") {
+					f, err := ParseCardFilter(strings.TrimPrefix(command, "!complete"))
+					if err != nil {
+						replyMsg = Tr(lang, "search.invalid", Vars{"Word": err.Error()})
+						forceWhisper = true
+					} else {
+						go func(player Player) {
+							if !s.FetchLibrary(player, 5*time.Second) {
+								s.WhisperTr(player, "library.unavailable", nil)
+								return
+							}
+							const maxNames = 15
+							cards := CompletionFor(player, f)
+							if len(cards) == 0 {
+								s.WhisperTr(player, "complete.none", nil)
+								return
+							}
+							OfferCompletion(player, cards)
+
+							names := sortedNames(cards)
+							goldSum := 0
+							for _, card := range names {
+								goldSum += s.DeterminePrice(card, 1, false)
+							}
+							more := 0
+							if len(names) > maxNames {
+								more = len(names) - maxNames
+								names = names[:maxNames]
+							}
+							discount := goldSum * Conf.CompletionDiscount / 100
+							s.WhisperTr(player, "complete.quote", Vars{
+								"Count":    len(cards),
+								"Names":    names,
+								"More":     more,
+								"Sum":      goldSum - discount,
+								"Discount": discount,
+								"Minutes":  Conf.CompletionMinutes,
+							})
+						}(m.From)
 					}
 				}
 
@@ -272,67 +435,329 @@ func startBot(helloMessage string) {
 							list = append(list, card)
 						}
 					}
-					replyMsg = fmt.Sprintf("I currently don't have %s. I'm paying extra for that!", strings.Join(list, ", "))
+					replyMsg = Tr(lang, "missing", Vars{"Cards": list})
 					forceWhisper = true
 				}
 
 				if command == "!stock" {
-					commons := 0
-					uncommons := 0
-					rares := 0
+					copies := make([]*Card, 0, len(Libraries[Bot].Cards))
 					uniques := make(map[string]bool)
 					totalValue := 0
 
-					for _, card := range Libraries[Bot].Cards {
-						name := CardTypes[CardId(card.TypeId)]
-						if uniques[name] == false {
-							totalValue += s.DeterminePrice(name, Stocks[Bot][name], false)
+					for _, libraryCard := range Libraries[Bot].Cards {
+						card, ok := Cards.ById(CardId(libraryCard.TypeId))
+						if !ok {
+							continue
 						}
-						uniques[name] = true
-						switch CardRarities[name] {
-						case 0:
-							commons++
-						case 1:
-							uncommons++
-						case 2:
-							rares++
+						if uniques[card.Name] == false {
+							totalValue += s.DeterminePrice(card.Name, Stocks[Bot][card.Name], false)
 						}
+						uniques[card.Name] = true
+						copies = append(copies, card)
 					}
 
 					totalValue += Gold
-
-					replyMsg = fmt.Sprintf("I have %d commons, %d uncommons and %d rares. That's %d%% of all card types, as well as %d gold. Total value is %dk gold.",
-						commons, uncommons, rares, 100*len(uniques)/len(CardTypes), GoldForTrade(), int(totalValue/1000))
+					byRarity := GroupBy(copies, "rarity")
+
+					replyMsg = Tr(lang, "stock", Vars{
+						"Commons":   len(byRarity["common"]),
+						"Uncommons": len(byRarity["uncommon"]),
+						"Rares":     len(byRarity["rare"]),
+						"Percent":   100 * len(uniques) / len(CardTypes),
+						"Gold":      GoldForTrade(),
+						"ValueK":    totalValue / 1000,
+					})
 				}
 
 				if command == "!help" && m.Channel != TradeRoom {
-					replyMsg = "You can whisper me WTS or WTB requests. If you're interested in trading, you can queue up with '!trade'. You can also check the '!stock'"
+					replyMsg = Tr(lang, "help", nil)
 				}
 
 				if command == "!uptime" {
-					replyMsg = fmt.Sprintf("Up since %s", time.Since(upSince))
+					replyMsg = Tr(lang, "uptime", Vars{"Duration": time.Since(upSince)})
 				}
 
 				if command == "!trade" || command == "!queue" {
 					// replyMsg = "I'm currently under reconstruction, please wait a few minutes and try again."
 
-					for i, player := range queue {
-						if player == m.From {
-							replyMsg = fmt.Sprintf("You are already queued for trading. Your position in the queue is %d.", i)
-							break
+					pos, added := Queue.Add(m.From)
+					if !added {
+						replyMsg = Tr(lang, "queue.already", Vars{"Position": pos})
+					} else if pos > 0 {
+						mention := Player("")
+						if m.Channel != "WHISPER" {
+							mention = m.From
 						}
+						replyMsg = Tr(lang, "queue.added", Vars{"Mention": mention, "Position": pos})
 					}
-					if replyMsg == "" {
-						queue = append(queue, m.From)
-						if len(queue) == 1 && !currentlyTrading {
-							chReadyToTrade <- true
+				}
+
+				if command == "!reserve" {
+					held := Reservations.HeldCards(m.From)
+					cards := make([]string, 0, len(held))
+					for _, card := range sortedNames(held) {
+						cards = append(cards, fmt.Sprintf("%dx %s", held[card], card))
+					}
+					replyMsg = Tr(lang, "reserve.list", Vars{"Cards": cards})
+					forceWhisper = true
+				}
+
+				if strings.HasPrefix(command, "!reserve ") {
+					requested, errors := parseCardList(strings.TrimPrefix(command, "!reserve "), Reservations.Available(m.From))
+					problems := parseProblems(lang, errors)
+					if len(requested) == 0 {
+						replyMsg = problems
+					} else {
+						held := Reservations.Reserve(m.From, requested, time.Duration(Conf.ReservationMinutes)*time.Minute)
+						cards := make([]string, 0, len(held))
+						missing := make([]string, 0)
+						for _, card := range sortedNames(held) {
+							cards = append(cards, fmt.Sprintf("%dx %s", held[card], card))
+						}
+						for _, card := range sortedNames(requested) {
+							if held[card] < requested[card] {
+								missing = append(missing, card)
+							}
+						}
+						replyMsg = Tr(lang, "reserve.held", Vars{
+							"Cards":    cards,
+							"Missing":  missing,
+							"Minutes":  Conf.ReservationMinutes,
+							"Problems": problems,
+						})
+					}
+					forceWhisper = true
+				}
+
+				if strings.HasPrefix(command, "!auction ") && ACL.IsAdmin(m.From) {
+					words := strings.Fields(strings.TrimPrefix(command, "!auction "))
+					reserve, minutes := 0, 0
+					cardName := ""
+					var options []string
+					if len(words) >= 3 {
+						reserve, _ = strconv.Atoi(strings.TrimSuffix(words[len(words)-2], "g"))
+						minutes, _ = strconv.Atoi(words[len(words)-1])
+						cardName, options = resolveCardName(strings.Join(words[:len(words)-2], " "))
+					}
+					if len(options) > 0 {
+						replyMsg = Tr(lang, "card.ambiguous", ParseError{Word: strings.Join(words[:len(words)-2], " "), Options: options})
+					} else if cardName == "" || reserve <= 0 || minutes <= 0 {
+						replyMsg = Tr(lang, "auction.usage", nil)
+					} else if auction, ok := Auctions.Open(cardName, reserve, time.Duration(minutes)*time.Minute); !ok {
+						replyMsg = Tr(lang, "auction.unavailable", Vars{"Card": cardName})
+					} else {
+						vars := Vars{"Card": auction.Card, "Reserve": auction.Reserve, "Minutes": minutes}
+						s.Announce("auction.opened", vars)
+						replyMsg = Tr(lang, "auction.opened", vars)
+						forceWhisper = true
+					}
+				}
+
+				if command == "!auctions" {
+					replyMsg = Tr(lang, "auction.list", Vars{"Auctions": Auctions.Running()})
+					forceWhisper = true
+				}
+
+				if command == "!bid" || strings.HasPrefix(command, "!bid ") {
+					cardName, amount, options := parseBid(strings.TrimPrefix(command, "!bid"))
+					if len(options) > 0 {
+						replyMsg = Tr(lang, "card.ambiguous", ParseError{Word: strings.TrimPrefix(command, "!bid "), Options: options})
+					} else if amount <= 0 {
+						replyMsg = Tr(lang, "auction.bid_usage", nil)
+					} else {
+						auction, outbid, errKey := Auctions.Bid(m.From, cardName, amount)
+						if errKey != "" {
+							replyMsg = Tr(lang, errKey, Vars{"Card": auction.Card, "Minimum": auction.MinimumBid()})
 						} else {
-							if m.Channel != "WHISPER" {
-								replyMsg = fmt.Sprintf("%s: ", m.From)
+							replyMsg = Tr(lang, "auction.bid_placed", Vars{"Card": auction.Card, "Amount": amount, "Ends": int(auction.Ends.Sub(time.Now()).Minutes()) + 1})
+							if outbid != "" {
+								s.WhisperTr(outbid, "auction.outbid", Vars{"Card": auction.Card, "Amount": amount, "Minimum": auction.MinimumBid()})
 							}
-							replyMsg += fmt.Sprintf("You are now queued for trading. Your position in the queue is %d.", len(queue)-1)
 						}
 					}
+					forceWhisper = true
+				}
+
+				if strings.HasPrefix(command, "!raffle ") && ACL.IsAdmin(m.From) {
+					list := strings.TrimPrefix(command, "!raffle ")
+					minutes := Conf.RaffleMinutes
+					if i := strings.LastIndex(list, " "); i >= 0 {
+						if n, err := strconv.Atoi(list[i+1:]); err == nil && n > 0 {
+							minutes = n
+							list = list[:i]
+						}
+					}
+					cards, errors := parseCardList(list, Reservations.Available(""))
+					if raffle, ok := Raffles.Open(cards, time.Duration(minutes)*time.Minute); !ok {
+						replyMsg = Tr(lang, "raffle.usage", nil)
+						if len(errors) > 0 {
+							replyMsg = parseProblems(lang, errors)
+						}
+					} else {
+						vars := Vars{"Cards": cardCounts(raffle.Cards), "Minutes": minutes, "Hash": raffle.SeedHash}
+						s.Announce("raffle.opened", vars)
+						replyMsg = Tr(lang, "raffle.opened", vars)
+					}
+					forceWhisper = true
+				}
+
+				if command == "!enter" {
+					if Raffles.Enter(m.From) == 0 {
+						replyMsg = Tr(lang, "raffle.none", nil)
+					} else {
+						prizes := make([]string, 0)
+						for _, raffle := range Raffles.Running() {
+							prizes = append(prizes, cardCounts(raffle.Cards)...)
+						}
+						replyMsg = Tr(lang, "raffle.entered", Vars{"Cards": prizes})
+					}
+					forceWhisper = true
+				}
+
+				if command == "!order" {
+					replyMsg = Tr(lang, "order.usage", nil)
+					forceWhisper = true
+				}
+
+				if strings.HasPrefix(command, "!order ") {
+					order, errors := parseOrder(strings.TrimPrefix(command, "!order "))
+					if order.Card == "" {
+						replyMsg = Tr(lang, "order.usage", nil)
+						if len(errors) > 0 {
+							replyMsg = parseProblems(lang, errors)
+						}
+					} else if !Orders.Place(m.From, order) {
+						replyMsg = Tr(lang, "order.too_many", Vars{"Max": Conf.MaxOrders})
+					} else {
+						replyMsg = Tr(lang, "order.placed", order)
+						go s.CheckOrders()
+					}
+					forceWhisper = true
+				}
+
+				if command == "!orders" {
+					replyMsg = Tr(lang, "order.list", Vars{"Orders": Orders.Get(m.From)})
+					forceWhisper = true
+				}
+
+				if strings.HasPrefix(command, "!cancelorder ") {
+					cardName, _ := resolveCardName(strings.TrimPrefix(command, "!cancelorder "))
+					if Orders.Cancel(m.From, cardName) {
+						replyMsg = Tr(lang, "order.cancelled", Vars{"Card": cardName})
+					} else {
+						replyMsg = Tr(lang, "order.usage", nil)
+					}
+					forceWhisper = true
+				}
+
+				if command == "!notify" {
+					subs := Notifications.Get(m.From)
+					cards := make([]string, len(subs))
+					for i, sub := range subs {
+						cards[i] = sub.Card
+					}
+					replyMsg = Tr(lang, "notify.list", Vars{"Cards": cards})
+					forceWhisper = true
+				}
+
+				if strings.HasPrefix(command, "!notify ") {
+					sub, options := parseSubscription(strings.TrimPrefix(command, "!notify "))
+					if len(options) > 0 {
+						replyMsg = Tr(lang, "card.ambiguous", ParseError{Word: strings.TrimPrefix(command, "!notify "), Options: options})
+					} else if sub.Card == "" {
+						replyMsg = Tr(lang, "notify.usage", nil)
+					} else {
+						Notifications.Add(m.From, sub)
+						replyMsg = Tr(lang, "notify.added", sub)
+					}
+					forceWhisper = true
+				}
+
+				if strings.HasPrefix(command, "!unnotify ") {
+					cardName, _ := resolveCardName(strings.TrimPrefix(command, "!unnotify "))
+					if Notifications.Remove(m.From, cardName) {
+						replyMsg = Tr(lang, "notify.removed", Vars{"Card": cardName})
+					} else {
+						replyMsg = Tr(lang, "notify.usage", nil)
+					}
+					forceWhisper = true
+				}
+
+				if strings.HasPrefix(command, "!alias ") && ACL.IsAdmin(m.From) {
+					split := strings.SplitN(strings.TrimPrefix(command, "!alias "), "=", 2)
+					alias := strings.TrimSpace(split[0])
+					cardName := ""
+					if len(split) == 2 {
+						cardName, _ = resolveCardName(split[1])
+					}
+					if alias == "" || cardName == "" {
+						replyMsg = Tr(lang, "alias.usage", nil)
+					} else {
+						Aliases.Set(alias, cardName)
+						replyMsg = Tr(lang, "alias.set", Vars{"Alias": alias, "Card": cardName})
+					}
+				}
+
+				if strings.HasPrefix(command, "!unalias ") && ACL.IsAdmin(m.From) {
+					alias := strings.TrimSpace(strings.TrimPrefix(command, "!unalias "))
+					Aliases.Set(alias, "")
+					replyMsg = Tr(lang, "alias.removed", Vars{"Alias": alias})
+				}
+
+				if strings.HasPrefix(command, "!compare ") && ACL.IsAdmin(m.From) {
+					name := strings.TrimSpace(strings.SplitN(text, " ", 2)[1])
+					if player, ok := FindPlayer(name); !ok {
+						replyMsg = Tr(lang, "compare.unknown", Vars{"Player": name})
+					} else {
+						go func(admin Player) {
+							if !s.FetchLibrary(player, 5*time.Second) {
+								s.WhisperTr(admin, "compare.unknown", Vars{"Player": player})
+								return
+							}
+							library, mine := LibraryOf(player), LibraryOf(Bot)
+							owned := OwnedCards(player)
+							theirs := make([]string, 0)
+							ours := make([]string, 0)
+							both := 0
+							for _, card := range library.TradableTypes() {
+								if len(mine.Tradable(card)) == 0 {
+									theirs = append(theirs, card)
+								} else {
+									both++
+								}
+							}
+							for _, card := range mine.TradableTypes() {
+								if owned[card] == 0 {
+									ours = append(ours, card)
+								}
+							}
+							s.WhisperTr(admin, "compare.result", Vars{
+								"Player": player,
+								"Theirs": theirs,
+								"Ours":   ours,
+								"Both":   both,
+							})
+						}(m.From)
+					}
+				}
+
+				if command == "!targets" && ACL.IsAdmin(m.From) {
+					replyMsg = Tr(lang, "targets.report", TargetReport(10))
+					forceWhisper = true
+				}
+
+				if command == "!lang" {
+					replyMsg = Tr(lang, "lang.current", Vars{"Available": LanguageNames()})
+				}
+
+				if strings.HasPrefix(command, "!lang ") {
+					newLang := strings.TrimSpace(strings.TrimPrefix(command, "!lang "))
+					if _, ok := Catalogs[newLang]; ok {
+						Languages.Set(m.From, newLang)
+						replyMsg = Tr(newLang, "lang.set", nil)
+					} else {
+						replyMsg = Tr(lang, "lang.unknown", Vars{"Language": newLang, "Available": LanguageNames()})
+					}
 				}
 
 				if replyMsg != "" {
@@ -341,8 +766,7 @@ func startBot(helloMessage string) {
 					} else {
 						if forceWhisper {
 							s.Whisper(m.From, replyMsg)
-							s.Whisper(m.From, "To avoid spamming the channel, please use this command only in whisper. "+
-								"By the way, you can use any other command in whisper as well!")
+							s.Whisper(m.From, Tr(lang, "whisper.reminder", nil))
 						} else {
 							s.Say(m.Channel, replyMsg)
 						}
@@ -359,7 +783,7 @@ func startBot(helloMessage string) {
 		chKillThread <- true
 		log.Print("Restarting in 5..")
 		time.Sleep(time.Second * 5)
-		startBot("I live again!")
+		startBot("bot.revived")
 	}()
 
 	for {
@@ -383,62 +807,12 @@ func startBot(helloMessage string) {
 	}
 }
 
-func matchCardName(input string) string {
-	if input == "" {
-		return input
-	}
-
-	minDist := 2
-	bestFit := input
-
-	for _, cardName := range CardTypes {
-		dist := Levenshtein(strings.ToLower(input), strings.ToLower(cardName))
-		if dist <= minDist {
-			minDist = dist
-			bestFit = cardName
-		}
-	}
-
-	if minDist > 0 {
-		for _, cardName := range CardTypes {
-			for _, substr := range strings.Split(cardName, " ") {
-				if substr == input {
-					return cardName
-				}
-			}
-		}
-
-		for _, cardName := range CardTypes {
-			if strings.Contains(strings.ToLower(cardName), strings.ToLower(input)) {
-				return cardName
-			}
-		}
-	}
-	return bestFit
-}
-
-func parseCardList(str string) (cards map[string]int, failedWords []string) {
-	cards = make(map[string]int)
-	failedWords = make([]string, 0)
-
-	for _, word := range strings.Split(str, ",") {
-		word = reInvalidChars.ReplaceAllString(strings.ToLower(word), "")
-
-		num := 1
-		match := reNumbers.FindStringSubmatch(word)
-		if len(match) == 2 {
-			num, _ = strconv.Atoi(match[1])
-			word = reNumbers.ReplaceAllString(word, "")
-		}
-		card := matchCardName(strings.Trim(word, " "))
-		_, ok := CardRarities[card]
-		if ok {
-			cards[card] = num
-		} else {
-			failedWords = append(failedWords, word)
-		}
+// punish gives the player a strike. reason is "limit" or "timeout" and picks the ban message, what
+// goes into the ACL event log.
+func (s *State) punish(player Player, reason string, what string) {
+	if banned := ACL.Strike(player, what); banned > 0 {
+		s.WhisperTr(player, "acl.banned", Vars{"Duration": banned, "Reason": reason})
 	}
-	return
 }
 
 func logTrade(ts TradeStatus) {
@@ -460,6 +834,10 @@ func logTrade(ts TradeStatus) {
 		return strings.Join(s, ",")
 	}
 
-	io.WriteString(file, fmt.Sprintf("%s: Traded with %s.\nTheir offer: [%dg] %s\nMy offer: [%dg] %s\n\n",
-		time.Now().String(), ts.Partner, ts.Their.Gold, list(ts.Their.Cards), ts.My.Gold, list(ts.My.Cards)))
+	swap := ""
+	if ts.Swap {
+		swap = " (swap)"
+	}
+	io.WriteString(file, fmt.Sprintf("%s: Traded with %s%s.\nTheir offer: [%dg] %s\nMy offer: [%dg] %s\n\n",
+		time.Now().String(), ts.Partner, swap, ts.Their.Gold, list(ts.Their.Cards), ts.My.Gold, list(ts.My.Cards)))
 }
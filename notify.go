@@ -0,0 +1,134 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type Subscription struct {
+	Card     string
+	MaxPrice int
+	Queue    bool
+}
+
+type NotifyStore struct {
+	sync.Mutex
+	filename string
+	Players  map[Player][]Subscription
+}
+
+var Notifications *NotifyStore
+
+func LoadNotifications(filename string) *NotifyStore {
+	n := &NotifyStore{filename: filename, Players: make(map[Player][]Subscription)}
+	loadJSON(filename, &n.Players)
+	return n
+}
+
+func (n *NotifyStore) save() {
+	saveJSON(n.filename, n.Players)
+}
+
+// Add replaces any earlier subscription of the player for the same card.
+func (n *NotifyStore) Add(player Player, sub Subscription) {
+	n.Lock()
+	defer n.Unlock()
+	n.remove(player, sub.Card)
+	n.Players[player] = append(n.Players[player], sub)
+	n.save()
+}
+
+func (n *NotifyStore) Remove(player Player, card string) bool {
+	n.Lock()
+	defer n.Unlock()
+	removed := n.remove(player, card)
+	if removed {
+		n.save()
+	}
+	return removed
+}
+
+func (n *NotifyStore) remove(player Player, card string) bool {
+	subs := n.Players[player]
+	for i, sub := range subs {
+		if sub.Card == card {
+			n.Players[player] = append(subs[:i:i], subs[i+1:]...)
+			if len(n.Players[player]) == 0 {
+				delete(n.Players, player)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func (n *NotifyStore) Get(player Player) []Subscription {
+	n.Lock()
+	defer n.Unlock()
+	return append([]Subscription(nil), n.Players[player]...)
+}
+
+// parseSubscription reads "<card> [max price] [queue]".
+func parseSubscription(str string) (sub Subscription, options []string) {
+	words := strings.Fields(str)
+	if len(words) > 0 && words[len(words)-1] == "queue" {
+		sub.Queue = true
+		words = words[:len(words)-1]
+	}
+	if len(words) > 1 {
+		price := strings.TrimSuffix(words[len(words)-1], "g")
+		if gold, err := strconv.Atoi(price); err == nil && gold > 0 {
+			sub.MaxPrice = gold
+			words = words[:len(words)-1]
+		}
+	}
+	sub.Card, options = resolveCardName(strings.Join(words, " "))
+	return sub, options
+}
+
+// CheckNotifications tells every subscriber whose card is in stock at an acceptable price, and queues
+// those who asked for it. Subscriptions are dropped once they fired.
+func (s *State) CheckNotifications() {
+	if Notifications == nil {
+		return
+	}
+	type hit struct {
+		player Player
+		sub    Subscription
+		price  int
+	}
+	hits := make([]hit, 0)
+
+	Notifications.Lock()
+	for player, subs := range Notifications.Players {
+		available := Reservations.Available(player)
+		for _, sub := range subs {
+			if available[sub.Card] <= 0 {
+				continue
+			}
+			price := s.DeterminePrice(sub.Card, 1, false)
+			if sub.MaxPrice == 0 || price <= sub.MaxPrice {
+				hits = append(hits, hit{player, sub, price})
+			}
+		}
+	}
+	for _, h := range hits {
+		Notifications.remove(h.player, h.sub.Card)
+	}
+	if len(hits) > 0 {
+		Notifications.save()
+	}
+	Notifications.Unlock()
+
+	for _, h := range hits {
+		position := -1
+		if h.sub.Queue && Queue != nil {
+			AddWTBRequest(h.player, h.sub.Card, 1)
+			if pos, added := Queue.Add(h.player); added {
+				position = pos
+			}
+		}
+		s.WhisperTr(h.player, "notify.available", Vars{"Card": h.sub.Card, "Price": h.price, "Position": position})
+	}
+}
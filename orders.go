@@ -0,0 +1,198 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Order struct {
+	Card     string
+	Num      int
+	MaxPrice int
+	Placed   time.Time
+	Filled   time.Time // when cards were last held for the order, zero once they were bought
+	Missed   int       // fills that lapsed without a trade in a row
+}
+
+// Paused says whether the order missed too many fills to be filled again. Placing it again resumes it.
+func (order Order) Paused() bool {
+	return order.Missed >= Conf.OrderMaxMisses
+}
+
+type OrderStore struct {
+	sync.Mutex
+	filename string
+	Players  map[Player][]Order
+}
+
+var Orders *OrderStore
+
+func LoadOrders(filename string) *OrderStore {
+	o := &OrderStore{filename: filename, Players: make(map[Player][]Order)}
+	loadJSON(filename, &o.Players)
+	return o
+}
+
+func (o *OrderStore) save() {
+	saveJSON(o.filename, o.Players)
+}
+
+// Place replaces any earlier order of the player for the same card. It fails if the player already has
+// Conf.MaxOrders other orders.
+func (o *OrderStore) Place(player Player, order Order) bool {
+	o.Lock()
+	defer o.Unlock()
+	o.cancel(player, order.Card)
+	if len(o.Players[player]) >= Conf.MaxOrders {
+		return false
+	}
+	o.Players[player] = append(o.Players[player], order)
+	o.save()
+	return true
+}
+
+func (o *OrderStore) Cancel(player Player, card string) bool {
+	o.Lock()
+	defer o.Unlock()
+	cancelled := o.cancel(player, card)
+	o.save()
+	return cancelled
+}
+
+func (o *OrderStore) cancel(player Player, card string) bool {
+	orders := o.Players[player]
+	for i, order := range orders {
+		if order.Card == card {
+			o.Players[player] = append(orders[:i:i], orders[i+1:]...)
+			if len(o.Players[player]) == 0 {
+				delete(o.Players, player)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func (o *OrderStore) Get(player Player) []Order {
+	o.Lock()
+	defer o.Unlock()
+	return append([]Order(nil), o.Players[player]...)
+}
+
+// parseOrder reads "[quantity] <card> <max price>".
+func parseOrder(str string) (order Order, errors []ParseError) {
+	words := strings.Fields(str)
+	if len(words) < 2 {
+		return order, nil
+	}
+	gold, err := strconv.Atoi(strings.TrimSuffix(words[len(words)-1], "g"))
+	if err != nil || gold <= 0 {
+		return order, nil
+	}
+	cards, errors := parseCardList(strings.Join(words[:len(words)-1], " "), nil)
+	if len(cards) != 1 {
+		return order, errors
+	}
+	for card, num := range cards {
+		order = Order{Card: card, Num: num, MaxPrice: gold, Placed: time.Now()}
+	}
+	return order, errors
+}
+
+// Settle reduces the player's orders by the cards they just bought from us and removes filled ones.
+func (o *OrderStore) Settle(player Player, bought map[string]int) {
+	o.Lock()
+	defer o.Unlock()
+	orders := o.Players[player]
+	filled := make([]string, 0)
+	for i := range orders {
+		if num := bought[orders[i].Card]; num > 0 {
+			orders[i].Num -= num
+			orders[i].Filled, orders[i].Missed = time.Time{}, 0
+			if orders[i].Num <= 0 {
+				filled = append(filled, orders[i].Card)
+			}
+		}
+	}
+	for _, card := range filled {
+		o.cancel(player, card)
+	}
+	o.save()
+}
+
+// CheckOrders fills orders from our stock as far as the price allows: the cards are reserved for the
+// player, who is put in the trade queue. Copies already held for the player count towards the order,
+// which is only reduced once the trade went through, see Settle. An order whose held cards lapsed
+// Conf.OrderMaxMisses times in a row is paused.
+func (s *State) CheckOrders() {
+	if Orders == nil {
+		return
+	}
+	type fill struct {
+		player Player
+		card   string
+		num    int
+		price  int
+		paused bool
+	}
+	fills := make([]fill, 0)
+
+	Orders.Lock()
+	for player, orders := range Orders.Players {
+		available := Reservations.Available(player)
+		held := Reservations.HeldCards(player)
+		for i := range orders {
+			order := &orders[i]
+			if order.Paused() {
+				continue
+			}
+			num := order.Num - held[order.Card]
+			if free := available[order.Card] - held[order.Card]; num > free {
+				num = free
+			}
+			for num > 0 && s.DeterminePrice(order.Card, num, false) > num*order.MaxPrice {
+				num--
+			}
+			if num <= 0 {
+				continue
+			}
+			before := held[order.Card]
+			if before == 0 && !order.Filled.IsZero() {
+				order.Missed++
+				if order.Paused() {
+					fills = append(fills, fill{player: player, card: order.Card, paused: true})
+					continue
+				}
+			}
+			held = Reservations.Hold(player, map[string]int{order.Card: before + num}, time.Duration(Conf.ReservationMinutes)*time.Minute)
+			if total := held[order.Card]; total > before {
+				order.Filled = time.Now()
+				fills = append(fills, fill{player, order.Card, total, s.DeterminePrice(order.Card, total, false), false})
+			}
+		}
+	}
+	Orders.save()
+	Orders.Unlock()
+
+	for _, f := range fills {
+		if f.paused {
+			s.WhisperTr(f.player, "order.paused", Vars{"Card": f.card, "Misses": Conf.OrderMaxMisses})
+			continue
+		}
+		AddWTBRequest(f.player, f.card, f.num)
+		position, added := -1, false
+		if Queue != nil {
+			position, added = Queue.Add(f.player)
+		}
+		s.WhisperTr(f.player, "order.filled", Vars{
+			"Card":     f.card,
+			"Num":      f.num,
+			"Price":    f.price,
+			"Position": position,
+			"Trading":  position == 0 && !added,
+			"Minutes":  Conf.ReservationMinutes,
+		})
+	}
+}
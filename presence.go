@@ -0,0 +1,74 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+const recentlySeen = 5 * time.Minute
+
+type PresenceTable struct {
+	sync.Mutex
+	rooms   map[Channel]map[Player]bool
+	friends map[Player]bool
+	seen    map[Player]time.Time
+}
+
+var Presence = &PresenceTable{
+	rooms:   make(map[Channel]map[Player]bool),
+	friends: make(map[Player]bool),
+	seen:    make(map[Player]time.Time),
+}
+
+func (p *PresenceTable) UpdateRoom(room Channel, reset bool, joined, left []Player) {
+	p.Lock()
+	defer p.Unlock()
+
+	if reset || p.rooms[room] == nil {
+		p.rooms[room] = make(map[Player]bool)
+	}
+	for _, player := range joined {
+		p.rooms[room][player] = true
+	}
+	for _, player := range left {
+		delete(p.rooms[room], player)
+	}
+}
+
+func (p *PresenceTable) LeaveRoom(room Channel) {
+	p.Lock()
+	defer p.Unlock()
+	delete(p.rooms, room)
+}
+
+func (p *PresenceTable) SetFriend(player Player, onlineState string) {
+	p.Lock()
+	defer p.Unlock()
+	p.friends[player] = onlineState == "ONLINE"
+}
+
+func (p *PresenceTable) Seen(player Player) {
+	p.Lock()
+	defer p.Unlock()
+	p.seen[player] = time.Now()
+}
+
+// IsPresent tells whether the player is around to accept a trade invite. Friends are judged by their
+// online state, everyone else by being in one of our rooms or having talked to us recently.
+func (p *PresenceTable) IsPresent(player Player) bool {
+	p.Lock()
+	defer p.Unlock()
+
+	if online, ok := p.friends[player]; ok && !online {
+		return false
+	}
+	for _, players := range p.rooms {
+		if players[player] {
+			return true
+		}
+	}
+	if online, ok := p.friends[player]; ok && online {
+		return true
+	}
+	return time.Since(p.seen[player]) < recentlySeen
+}
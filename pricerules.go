@@ -0,0 +1,78 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+)
+
+// PriceRule sets the price band, the long-term base price and the floor for all cards matching its
+// selectors. Empty selectors match everything, zero values are left to less specific rules.
+type PriceRule struct {
+	Rarity   string
+	Resource string
+	Kind     string
+	Set      int
+
+	Lower int
+	Upper int
+	Base  int
+	Floor int
+}
+
+func (r PriceRule) specificity() int {
+	n := 0
+	for _, selector := range []string{r.Rarity, r.Resource, r.Kind} {
+		if selector != "" {
+			n++
+		}
+	}
+	if r.Set != 0 {
+		n++
+	}
+	return n
+}
+
+func (r PriceRule) Matches(card *Card) bool {
+	return (r.Rarity == "" || r.Rarity == card.RarityName()) &&
+		(r.Resource == "" || r.Resource == card.Resource) &&
+		(r.Kind == "" || r.Kind == card.Kind) &&
+		(r.Set == 0 || strconv.Itoa(r.Set) == card.Attribute("set"))
+}
+
+// PriceRuleFor merges all rules matching the card, most specific first. Rules of equal specificity
+// are applied in configuration order.
+func PriceRuleFor(cardName string) (rule PriceRule, ok bool) {
+	card, ok := Cards.ByName(cardName)
+	if !ok {
+		return rule, false
+	}
+
+	matching := make([]PriceRule, 0)
+	for _, r := range Conf.PriceRules {
+		if r.Matches(card) {
+			matching = append(matching, r)
+		}
+	}
+	if len(matching) == 0 {
+		return rule, false
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		return matching[i].specificity() > matching[j].specificity()
+	})
+
+	for _, r := range matching {
+		if rule.Lower == 0 {
+			rule.Lower = r.Lower
+		}
+		if rule.Upper == 0 {
+			rule.Upper = r.Upper
+		}
+		if rule.Base == 0 {
+			rule.Base = r.Base
+		}
+		if rule.Floor == 0 {
+			rule.Floor = r.Floor
+		}
+	}
+	return rule, true
+}
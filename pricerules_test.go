@@ -0,0 +1,75 @@
+package main
+
+import "testing"
+
+// useCards replaces the card catalog with cards.
+func useCards(cards ...*Card) {
+	byId := make(map[CardId]*Card)
+	byName := make(map[string]*Card)
+	for _, card := range cards {
+		byId[card.Id] = card
+		byName[card.Name] = card
+	}
+	Cards.Lock()
+	Cards.byId, Cards.byName = byId, byName
+	Cards.Unlock()
+}
+
+var (
+	wolf   = &Card{Id: 1, Name: "Wolf", Kind: "creature", Rarity: 0, Resource: "growth", Set: 1}
+	dragon = &Card{Id: 2, Name: "Dragon", Kind: "creature", Rarity: 2, Resource: "energy", Set: 2}
+	bolt   = &Card{Id: 3, Name: "Bolt", Kind: "spell", Rarity: 2, Resource: "energy", Set: 1}
+)
+
+func TestPriceRuleMatches(t *testing.T) {
+	for _, test := range []struct {
+		rule PriceRule
+		card *Card
+		want bool
+	}{
+		{PriceRule{}, wolf, true},
+		{PriceRule{Rarity: "common"}, wolf, true},
+		{PriceRule{Rarity: "rare"}, wolf, false},
+		{PriceRule{Resource: "energy", Kind: "creature"}, dragon, true},
+		{PriceRule{Resource: "energy", Kind: "creature"}, bolt, false},
+		{PriceRule{Set: 1}, bolt, true},
+		{PriceRule{Set: 1}, dragon, false},
+		{PriceRule{Rarity: "rare", Resource: "energy", Kind: "spell", Set: 1}, bolt, true},
+	} {
+		if got := test.rule.Matches(test.card); got != test.want {
+			t.Errorf("%+v.Matches(%s) = %v, want %v", test.rule, test.card.Name, got, test.want)
+		}
+	}
+}
+
+func TestPriceRuleFor(t *testing.T) {
+	useCards(wolf, dragon, bolt)
+	defer func(rules []PriceRule) { Conf.PriceRules = rules }(Conf.PriceRules)
+	Conf.PriceRules = []PriceRule{
+		{Lower: 50, Upper: 500, Base: 100, Floor: 10},
+		{Rarity: "rare", Lower: 400, Upper: 4000},
+		{Rarity: "rare", Upper: 3000, Base: 1500},
+		{Rarity: "rare", Kind: "spell", Upper: 2500},
+		{Set: 2, Floor: 300},
+	}
+
+	for _, test := range []struct {
+		card string
+		want PriceRule
+		ok   bool
+	}{
+		{"Wolf", PriceRule{Lower: 50, Upper: 500, Base: 100, Floor: 10}, true},
+		{"Dragon", PriceRule{Lower: 400, Upper: 4000, Base: 1500, Floor: 300}, true},
+		{"Bolt", PriceRule{Lower: 400, Upper: 2500, Base: 1500, Floor: 10}, true},
+		{"Unknown", PriceRule{}, false},
+	} {
+		if got, ok := PriceRuleFor(test.card); got != test.want || ok != test.ok {
+			t.Errorf("PriceRuleFor(%s) = %+v, %v, want %+v, %v", test.card, got, ok, test.want, test.ok)
+		}
+	}
+
+	Conf.PriceRules = []PriceRule{{Rarity: "uncommon", Base: 200}}
+	if _, ok := PriceRuleFor("Wolf"); ok {
+		t.Error("PriceRuleFor found a rule although none matches")
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+// Protection describes cards we keep no matter what. MinCounts and KeepOne count every copy we own,
+// tradable or not, while copies at MinLevel or above are always kept (0 turns that off).
+type Protection struct {
+	KeepOne   bool
+	MinCounts map[string]int
+	MinLevel  int
+}
+
+// protectedIds picks the tradable copies in the library we must keep, taking the ones we'd offer last.
+func protectedIds(index *LibraryIndex) map[int]bool {
+	p := Conf.Protected
+	protected := make(map[int]bool)
+	for name, ids := range index.tradable {
+		keep := p.MinCounts[name]
+		if p.KeepOne && keep < 1 {
+			keep = 1
+		}
+		keep -= index.owned[name] - len(ids)
+		for i := len(ids) - 1; i >= 0; i-- {
+			card := index.byId[ids[i]]
+			if keep > 0 || (p.MinLevel > 0 && card.Level >= p.MinLevel) {
+				protected[card.Id] = true
+				keep--
+			}
+		}
+	}
+	return protected
+}
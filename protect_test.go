@@ -0,0 +1,65 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// testLibrary indexes cards the way IndexLibrary does.
+func testLibrary(cards ...LibraryCard) *LibraryIndex {
+	index := &LibraryIndex{
+		byId:     make(map[int]*LibraryCard),
+		tradable: make(map[string][]int),
+		owned:    make(map[string]int),
+	}
+	for i := range cards {
+		card := &cards[i]
+		index.byId[card.Id] = card
+		index.owned[card.Name]++
+		if card.Tradable {
+			index.tradable[card.Name] = append(index.tradable[card.Name], card.Id)
+		}
+	}
+	for _, ids := range index.tradable {
+		sort.Slice(ids, func(i, j int) bool { return offerOrder(index.byId[ids[i]], index.byId[ids[j]]) })
+	}
+	return index
+}
+
+func TestProtectedIds(t *testing.T) {
+	defer func(p Protection, selection string) { Conf.Protected, Conf.CardSelection = p, selection }(Conf.Protected, Conf.CardSelection)
+	Conf.CardSelection = "lowest_level"
+	index := testLibrary(
+		LibraryCard{1, "Wolf", true, 0},
+		LibraryCard{2, "Wolf", true, 0},
+		LibraryCard{3, "Wolf", true, 1},
+		LibraryCard{4, "Bear", true, 0},
+		LibraryCard{5, "Bear", false, 0},
+		LibraryCard{6, "Bolt", true, 2},
+	)
+
+	for _, test := range []struct {
+		name string
+		p    Protection
+		want []int
+	}{
+		{"nothing", Protection{}, []int{}},
+		{"one of each", Protection{KeepOne: true}, []int{3, 6}},
+		{"minimum count", Protection{MinCounts: map[string]int{"Wolf": 2}}, []int{2, 3}},
+		{"more than we have", Protection{MinCounts: map[string]int{"Wolf": 5}}, []int{1, 2, 3}},
+		{"untradable copies count", Protection{MinCounts: map[string]int{"Bear": 2}}, []int{4}},
+		{"minimum level", Protection{MinLevel: 1}, []int{3, 6}},
+		{"one of each and high levels", Protection{KeepOne: true, MinLevel: 2}, []int{3, 6}},
+	} {
+		Conf.Protected = test.p
+		got := make([]int, 0)
+		for id := range protectedIds(index) {
+			got = append(got, id)
+		}
+		sort.Ints(got)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: protectedIds = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
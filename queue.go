@@ -0,0 +1,102 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+type TradeQueue struct {
+	sync.Mutex
+	Ready   chan bool
+	current Player
+	waiting []Player
+	skips   map[Player]int
+	busy    bool
+}
+
+var Queue *TradeQueue
+
+func NewTradeQueue() *TradeQueue {
+	return &TradeQueue{
+		Ready: make(chan bool, 100),
+		skips: make(map[Player]int),
+	}
+}
+
+// Add puts the player at the end of the queue and returns their position. Position 0 means they're
+// the current trade partner or will be invited right away.
+func (q *TradeQueue) Add(player Player) (pos int, added bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.current == player {
+		return 0, false
+	}
+	for i, p := range q.waiting {
+		if p == player {
+			return i + 1, false
+		}
+	}
+
+	q.waiting = append(q.waiting, player)
+	if !q.busy {
+		q.busy = true
+		q.Ready <- true
+		return 0, true
+	}
+	return len(q.waiting), true
+}
+
+func (q *TradeQueue) Current() Player {
+	q.Lock()
+	defer q.Unlock()
+	return q.current
+}
+
+func (q *TradeQueue) Waiting() []Player {
+	q.Lock()
+	defer q.Unlock()
+	return append([]Player(nil), q.waiting...)
+}
+
+// Next picks the first present player from the queue. Absent players keep their position, but are
+// dropped after Conf.QueueMaxSkips skips. returning is set if the chosen player had been skipped before.
+// If nobody in the queue is present, ok is false and Next should be retried later via Retry.
+func (q *TradeQueue) Next(isPresent func(Player) bool) (player Player, returning bool, dropped []Player, ok bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	q.current = ""
+	remaining := make([]Player, 0, len(q.waiting))
+	for _, p := range q.waiting {
+		if !ok && isPresent(p) {
+			player, ok = p, true
+			returning = q.skips[p] > 0
+			delete(q.skips, p)
+			continue
+		}
+		if !ok {
+			q.skips[p]++
+			if q.skips[p] > Conf.QueueMaxSkips {
+				delete(q.skips, p)
+				dropped = append(dropped, p)
+				continue
+			}
+		}
+		remaining = append(remaining, p)
+	}
+	q.waiting = remaining
+
+	if ok {
+		q.current = player
+	} else if len(q.waiting) == 0 {
+		q.busy = false
+	}
+	return
+}
+
+func (q *TradeQueue) Retry() {
+	time.AfterFunc(time.Duration(Conf.QueueRetrySeconds)*time.Second, func() {
+		q.Ready <- true
+	})
+}
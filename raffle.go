@@ -0,0 +1,295 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Raffle struct {
+	Id       int
+	Cards    map[string]int
+	Ends     time.Time
+	Entrants []Player
+	SeedHash string
+	Seed     string
+
+	Drawn      bool
+	Winner     Player
+	ClaimUntil time.Time
+	Claimed    bool
+}
+
+type RaffleStore struct {
+	sync.Mutex
+	filename string
+	NextId   int
+	Raffles  []*Raffle
+
+	// Pool collects donated cards until their value reaches Conf.RaffleDonationValue.
+	Pool      map[string]int
+	PoolValue int
+}
+
+var Raffles *RaffleStore
+
+func LoadRaffles(filename string) *RaffleStore {
+	r := &RaffleStore{filename: filename, NextId: 1, Pool: make(map[string]int)}
+	loadJSON(filename, r)
+	return r
+}
+
+func (r *RaffleStore) save() {
+	saveJSON(r.filename, r)
+}
+
+func raffleHolder(raffle *Raffle) Player {
+	return Player(fmt.Sprintf("#raffle%d", raffle.Id))
+}
+
+// raffleDraw picks the winner from sha256(seed + ":" + entrants joined by ","), so anyone can verify
+// the draw once the seed is revealed.
+func raffleDraw(seed string, entrants []Player) Player {
+	names := make([]string, len(entrants))
+	for i, player := range entrants {
+		names[i] = string(player)
+	}
+	sum := sha256.Sum256([]byte(seed + ":" + strings.Join(names, ",")))
+	return entrants[binary.BigEndian.Uint64(sum[:8])%uint64(len(entrants))]
+}
+
+// Open reserves the cards for a new raffle. It fails if none of them are available.
+func (r *RaffleStore) Open(cards map[string]int, d time.Duration) (*Raffle, bool) {
+	r.Lock()
+	defer r.Unlock()
+	return r.open(cards, d)
+}
+
+func (r *RaffleStore) open(cards map[string]int, d time.Duration) (*Raffle, bool) {
+	seed := make([]byte, 16)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, false
+	}
+	raffle := &Raffle{Id: r.NextId, Ends: time.Now().Add(d), Seed: hex.EncodeToString(seed)}
+	sum := sha256.Sum256([]byte(raffle.Seed))
+	raffle.SeedHash = hex.EncodeToString(sum[:])
+
+	raffle.Cards = Reservations.Hold(raffleHolder(raffle), cards, d)
+	if len(raffle.Cards) == 0 {
+		return nil, false
+	}
+	r.NextId++
+	r.Raffles = append(r.Raffles, raffle)
+	r.save()
+	return raffle, true
+}
+
+// Enter adds the player to every running raffle and returns how many they're entered in.
+func (r *RaffleStore) Enter(player Player) (entered int) {
+	r.Lock()
+	defer r.Unlock()
+	for _, raffle := range r.Raffles {
+		if raffle.Drawn || time.Now().After(raffle.Ends) {
+			continue
+		}
+		already := false
+		for _, p := range raffle.Entrants {
+			already = already || p == player
+		}
+		if !already {
+			raffle.Entrants = append(raffle.Entrants, player)
+		}
+		entered++
+	}
+	r.save()
+	return entered
+}
+
+func (r *RaffleStore) Running() []Raffle {
+	r.Lock()
+	defer r.Unlock()
+	running := make([]Raffle, 0)
+	for _, raffle := range r.Raffles {
+		if !raffle.Drawn {
+			running = append(running, *raffle)
+		}
+	}
+	return running
+}
+
+// Donated adds cards given away in a donation trade to the pool for the next automatic raffle.
+func (r *RaffleStore) Donated(cards map[string]int, value int) {
+	if Conf.RaffleDonationValue <= 0 {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	for card, num := range cards {
+		r.Pool[card] += num
+	}
+	r.PoolValue += value
+	r.save()
+}
+
+// PrizeFor returns the cards the player won and hasn't claimed yet.
+func (r *RaffleStore) PrizeFor(player Player) map[string]int {
+	r.Lock()
+	defer r.Unlock()
+	prize := make(map[string]int)
+	for _, raffle := range r.Raffles {
+		if raffle.Drawn && !raffle.Claimed && raffle.Winner == player && time.Now().Before(raffle.ClaimUntil) {
+			for card, num := range raffle.Cards {
+				prize[card] += num
+			}
+		}
+	}
+	return prize
+}
+
+// Claim takes the cards the player just got from us off their unclaimed prizes. A prize is claimed once
+// all of its cards were traded.
+func (r *RaffleStore) Claim(player Player, cards map[string]int) {
+	r.Lock()
+	defer r.Unlock()
+	traded := make(map[string]int)
+	for card, num := range cards {
+		traded[card] = num
+	}
+	for _, raffle := range r.Raffles {
+		if !raffle.Drawn || raffle.Claimed || raffle.Winner != player {
+			continue
+		}
+		left := make(map[string]int)
+		for card, num := range raffle.Cards {
+			taken := min(num, traded[card])
+			traded[card] -= taken
+			if num > taken {
+				left[card] = num - taken
+			}
+		}
+		raffle.Cards = left
+		raffle.Claimed = len(left) == 0
+	}
+	r.save()
+}
+
+// holdPrizes reserves unclaimed prizes for their winners again, after a trade released them or the bot
+// restarted. Copies won at auction are held on top.
+func (r *RaffleStore) holdPrizes() {
+	won := make(map[Player]map[string]int)
+	until := make(map[Player]time.Time)
+	for _, raffle := range r.Raffles {
+		if !raffle.Drawn || raffle.Winner == "" || raffle.Claimed || time.Now().After(raffle.ClaimUntil) {
+			continue
+		}
+		if won[raffle.Winner] == nil {
+			won[raffle.Winner] = make(map[string]int)
+		}
+		for card, num := range raffle.Cards {
+			won[raffle.Winner][card] += num
+		}
+		if raffle.ClaimUntil.After(until[raffle.Winner]) {
+			until[raffle.Winner] = raffle.ClaimUntil
+		}
+	}
+	for player, cards := range won {
+		held := Reservations.HeldCards(player)
+		for card := range cards {
+			cards[card] += len(Auctions.WonBy(player, card))
+		}
+		for card, num := range cards {
+			if held[card] < num {
+				Reservations.Hold(player, cards, until[player].Sub(time.Now()))
+				break
+			}
+		}
+	}
+}
+
+// RunRaffles opens a raffle from the donation pool once it's worth enough, keeps the prizes of running
+// raffles reserved and draws the ones that ended. Winners get their prize reserved and are queued.
+func (s *State) RunRaffles() {
+	type result struct {
+		raffle Raffle
+		opened bool
+	}
+	results := make([]result, 0)
+
+	Raffles.Lock()
+	Raffles.holdPrizes()
+	if Conf.RaffleDonationValue > 0 && Raffles.PoolValue >= Conf.RaffleDonationValue {
+		if raffle, ok := Raffles.open(Raffles.Pool, time.Duration(Conf.RaffleMinutes)*time.Minute); ok {
+			results = append(results, result{*raffle, true})
+		}
+		Raffles.Pool = make(map[string]int)
+		Raffles.PoolValue = 0
+	}
+
+	claim := time.Duration(Conf.RaffleClaimMinutes) * time.Minute
+	changed := len(results) > 0
+	kept := Raffles.Raffles[:0]
+	for _, raffle := range Raffles.Raffles {
+		if !raffle.Drawn && time.Now().Before(raffle.Ends) {
+			Reservations.Hold(raffleHolder(raffle), raffle.Cards, raffle.Ends.Sub(time.Now()))
+		} else if !raffle.Drawn {
+			raffle.Drawn = true
+			Reservations.Release(raffleHolder(raffle))
+			if len(raffle.Entrants) > 0 {
+				raffle.Winner = raffleDraw(raffle.Seed, raffle.Entrants)
+				raffle.ClaimUntil = time.Now().Add(claim)
+				held := Reservations.HeldCards(raffle.Winner)
+				prize := make(map[string]int)
+				for card, num := range raffle.Cards {
+					prize[card] = held[card] + num
+				}
+				Reservations.Hold(raffle.Winner, prize, claim)
+			}
+			results = append(results, result{*raffle, false})
+			changed = true
+		}
+		if !raffle.Drawn || (raffle.Winner != "" && !raffle.Claimed && time.Now().Before(raffle.ClaimUntil)) {
+			kept = append(kept, raffle)
+		} else {
+			changed = true
+		}
+	}
+	Raffles.Raffles = kept
+	if changed {
+		Raffles.save()
+	}
+	Raffles.Unlock()
+
+	for _, res := range results {
+		raffle := res.raffle
+		vars := Vars{
+			"Cards":    cardCounts(raffle.Cards),
+			"Minutes":  Conf.RaffleMinutes,
+			"Hash":     raffle.SeedHash,
+			"Seed":     raffle.Seed,
+			"Count":    len(raffle.Entrants),
+			"Entrants": raffle.Entrants,
+			"Winner":   raffle.Winner,
+		}
+		switch {
+		case res.opened:
+			s.Announce("raffle.opened", vars)
+		case raffle.Winner == "":
+			s.Announce("raffle.no_entrants", vars)
+		default:
+			// The entrants go out in draw order next to the committed hash before the seed is revealed, so
+			// anyone can recompute the winner with raffleDraw.
+			s.Announce("raffle.closed", vars)
+			s.Announce("raffle.drawn", vars)
+			position, added := Queue.Add(raffle.Winner)
+			vars["Position"] = position
+			vars["Trading"] = position == 0 && !added
+			vars["Minutes"] = Conf.RaffleClaimMinutes
+			s.WhisperTr(raffle.Winner, "raffle.you_won", vars)
+		}
+	}
+}
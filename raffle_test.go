@@ -0,0 +1,42 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHoldPrizes(t *testing.T) {
+	defer func(res *ReservationStore, a *AuctionStore, max int) {
+		Reservations, Auctions, Conf.ReservationMaxCards = res, a, max
+	}(Reservations, Auctions, Conf.ReservationMaxCards)
+	Conf.ReservationMaxCards = 1
+	Reservations = &ReservationStore{holds: make(map[int]Hold)}
+	Auctions = &AuctionStore{filename: filepath.Join(t.TempDir(), "auctions.json")}
+	useBotLibrary(t, testLibrary(
+		LibraryCard{1, "Wolf", true, 0},
+		LibraryCard{2, "Wolf", true, 0},
+		LibraryCard{3, "Bolt", true, 0},
+	))
+	r := &RaffleStore{Raffles: []*Raffle{{
+		Id:         1,
+		Cards:      map[string]int{"Wolf": 2},
+		Drawn:      true,
+		Winner:     "alice",
+		ClaimUntil: time.Now().Add(time.Hour),
+	}}}
+
+	r.holdPrizes()
+	if held := Reservations.HeldCards("alice"); held["Wolf"] != 2 {
+		t.Fatalf("alice holds %v, want both wolves she won", held)
+	}
+	if held := Reservations.Reserve("alice", map[string]int{"Bolt": 1}, time.Hour); held["Bolt"] != 1 {
+		t.Errorf("the prize counted towards alice's reservation cap")
+	}
+
+	Reservations.Release("alice")
+	r.holdPrizes()
+	if held := Reservations.HeldCards("alice"); held["Wolf"] != 2 {
+		t.Errorf("after a trade released them alice holds %v, want her unclaimed wolves again", held)
+	}
+}
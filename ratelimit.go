@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+type RateLimiter struct {
+	sync.Mutex
+	day     int
+	lastUse map[Player]map[string]time.Time
+	warned  map[Player]map[string]bool
+	uses    map[Player]map[string]int
+}
+
+var Limiter = NewRateLimiter()
+
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		lastUse: make(map[Player]map[string]time.Time),
+		warned:  make(map[Player]map[string]bool),
+		uses:    make(map[Player]map[string]int),
+	}
+}
+
+func commandName(command string) string {
+	key := strings.SplitN(command, " ", 2)[0]
+	if key == "!queue" {
+		key = "!trade"
+	}
+	return key
+}
+
+// Allow checks the command against its cooldown and daily limit. If it's not allowed, reason is set to
+// a catalog key unless the player has already been told since the limit was hit.
+func (r *RateLimiter) Allow(player Player, command string) (ok bool, reason string, vars Vars) {
+	r.Lock()
+	defer r.Unlock()
+
+	key := commandName(command)
+	limit, limited := Conf.CommandLimits[key]
+	if !limited {
+		return true, "", nil
+	}
+
+	if day := time.Now().YearDay(); day != r.day {
+		r.day = day
+		r.uses = make(map[Player]map[string]int)
+	}
+	if r.lastUse[player] == nil {
+		r.lastUse[player] = make(map[string]time.Time)
+		r.warned[player] = make(map[string]bool)
+		r.uses[player] = make(map[string]int)
+	} else if r.uses[player] == nil {
+		r.uses[player] = make(map[string]int)
+	}
+
+	vars = Vars{"Command": key}
+	if limit.Daily > 0 && r.uses[player][key] >= limit.Daily {
+		reason = "limit.daily"
+	} else if wait := r.lastUse[player][key].Add(time.Duration(limit.CooldownSeconds) * time.Second).Sub(time.Now()); wait > 0 {
+		reason = "limit.cooldown"
+		vars["Seconds"] = int(wait.Seconds()) + 1
+	} else {
+		r.lastUse[player][key] = time.Now()
+		r.warned[player][key] = false
+		r.uses[player][key]++
+		return true, "", nil
+	}
+
+	if r.warned[player][key] {
+		reason = ""
+	}
+	r.warned[player][key] = true
+	return false, reason, vars
+}
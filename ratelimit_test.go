@@ -0,0 +1,30 @@
+package main
+
+import "testing"
+
+func TestRateLimiterWarnsOncePerWindow(t *testing.T) {
+	r := NewRateLimiter()
+
+	if ok, _, _ := r.Allow("p", "!price bear"); !ok {
+		t.Fatal("first use was limited")
+	}
+	reasons := make([]string, 0)
+	for i := 0; i < 5; i++ {
+		ok, reason, _ := r.Allow("p", "!price wolf")
+		if ok {
+			t.Fatal("use within the cooldown was allowed")
+		}
+		reasons = append(reasons, reason)
+	}
+	if reasons[0] != "limit.cooldown" {
+		t.Errorf("first refusal gave reason %q, want limit.cooldown", reasons[0])
+	}
+	for i, reason := range reasons[1:] {
+		if reason != "" {
+			t.Errorf("refusal %d gave reason %q, want none", i+2, reason)
+		}
+	}
+	if ok, _, _ := r.Allow("p", "!help"); !ok {
+		t.Error("unlimited command was limited")
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+type Hold struct {
+	Player   Player
+	Card     string
+	Expires  time.Time
+	Reserved bool // asked for with !reserve, and so counted towards Conf.ReservationMaxCards
+}
+
+// ReservationStore keeps specific cards of ours aside for a player until their trade or until the hold expires.
+type ReservationStore struct {
+	sync.Mutex
+	holds   map[int]Hold
+	offered map[int]bool // our cards in the running trade window
+}
+
+var Reservations = &ReservationStore{holds: make(map[int]Hold)}
+
+func (r *ReservationStore) prune() {
+	for id, hold := range r.holds {
+		if time.Now().After(hold.Expires) {
+			delete(r.holds, id)
+		}
+	}
+}
+
+// Offer records which of our cards are in the running trade, so no new hold takes them.
+func (r *ReservationStore) Offer(ids []int) {
+	r.Lock()
+	defer r.Unlock()
+	r.offered = make(map[int]bool, len(ids))
+	for _, id := range ids {
+		r.offered[id] = true
+	}
+}
+
+// Refresh drops the holds on cards that aren't in our library anymore.
+func (r *ReservationStore) Refresh(library *LibraryIndex) {
+	r.Lock()
+	defer r.Unlock()
+	for id := range r.holds {
+		if _, ok := library.byId[id]; !ok {
+			delete(r.holds, id)
+		}
+	}
+}
+
+// Hold makes sure the player holds up to num copies of each card, as far as we have them, and keeps the
+// holds on those cards for at least d. It returns how many copies of each card they hold now.
+func (r *ReservationStore) Hold(player Player, cards map[string]int, d time.Duration) map[string]int {
+	return r.hold(player, cards, d, -1)
+}
+
+// Reserve is Hold for the !reserve command, which keeps the player within Conf.ReservationMaxCards.
+func (r *ReservationStore) Reserve(player Player, cards map[string]int, d time.Duration) map[string]int {
+	return r.hold(player, cards, d, Conf.ReservationMaxCards)
+}
+
+func (r *ReservationStore) hold(player Player, cards map[string]int, d time.Duration, limit int) map[string]int {
+	r.Lock()
+	defer r.Unlock()
+	r.prune()
+
+	expires := time.Now().Add(d)
+	held := make(map[string]int)
+	total := 0
+	for id, hold := range r.holds {
+		if hold.Player != player {
+			continue
+		}
+		if cards[hold.Card] > 0 && hold.Expires.Before(expires) {
+			hold.Expires = expires
+			r.holds[id] = hold
+		}
+		held[hold.Card]++
+		if hold.Reserved {
+			total++
+		}
+	}
+
+	exclude := make(map[int]bool)
+	for id := range r.holds {
+		exclude[id] = true
+	}
+	for id := range r.offered {
+		exclude[id] = true
+	}
+	for id := range LibraryOf(Bot).Protected() {
+		exclude[id] = true
+	}
+	for _, card := range sortedNames(cards) {
+		num := cards[card] - held[card]
+		if limit >= 0 && num > limit-total {
+			num = limit - total
+		}
+		for _, id := range botCardIds(card, num, exclude) {
+			r.holds[id] = Hold{player, card, expires, limit >= 0}
+			held[card]++
+			total++
+		}
+	}
+	return held
+}
+
+// HeldByOthers returns the ids of all cards reserved for anyone but the player.
+func (r *ReservationStore) HeldByOthers(player Player) map[int]bool {
+	r.Lock()
+	defer r.Unlock()
+	held := make(map[int]bool)
+	for id, hold := range r.holds {
+		if hold.Player != player && time.Now().Before(hold.Expires) {
+			held[id] = true
+		}
+	}
+	return held
+}
+
+func (r *ReservationStore) Held(player Player) []int {
+	r.Lock()
+	defer r.Unlock()
+	r.prune()
+	ids := make([]int, 0)
+	for id, hold := range r.holds {
+		if hold.Player == player {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (r *ReservationStore) HeldCards(player Player) map[string]int {
+	r.Lock()
+	defer r.Unlock()
+	r.prune()
+	held := make(map[string]int)
+	for _, hold := range r.holds {
+		if hold.Player == player {
+			held[hold.Card]++
+		}
+	}
+	return held
+}
+
+func (r *ReservationStore) Release(player Player) {
+	r.Lock()
+	defer r.Unlock()
+	for id, hold := range r.holds {
+		if hold.Player == player {
+			delete(r.holds, id)
+		}
+	}
+}
+
+// Available is our stock as the player sees it, without the cards held for anyone else or protected.
+func (r *ReservationStore) Available(player Player) map[string]int {
+	r.Lock()
+	defer r.Unlock()
+	r.prune()
+	library := LibraryOf(Bot)
+	available := make(map[string]int, len(Stocks[Bot]))
+	for card, num := range Stocks[Bot] {
+		available[card] = num - library.ProtectedCount(card)
+	}
+	for _, hold := range r.holds {
+		if hold.Player != player {
+			available[hold.Card]--
+		}
+	}
+	return available
+}
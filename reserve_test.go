@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func useBotLibrary(t *testing.T, index *LibraryIndex) {
+	libraryIndexes.Lock()
+	old, had := libraryIndexes.players[Bot]
+	libraryIndexes.players[Bot] = index
+	libraryIndexes.Unlock()
+	t.Cleanup(func() {
+		libraryIndexes.Lock()
+		defer libraryIndexes.Unlock()
+		if had {
+			libraryIndexes.players[Bot] = old
+		} else {
+			delete(libraryIndexes.players, Bot)
+		}
+	})
+}
+
+func TestHold(t *testing.T) {
+	defer func(max int) { Conf.ReservationMaxCards = max }(Conf.ReservationMaxCards)
+	Conf.ReservationMaxCards = 2
+	useBotLibrary(t, testLibrary(
+		LibraryCard{1, "Wolf", true, 0},
+		LibraryCard{2, "Wolf", true, 0},
+		LibraryCard{3, "Wolf", true, 0},
+		LibraryCard{4, "Bolt", true, 0},
+	))
+	r := &ReservationStore{holds: make(map[int]Hold)}
+
+	if held := r.Reserve("alice", map[string]int{"Wolf": 3}, time.Hour); held["Wolf"] != 2 {
+		t.Errorf("Reserve held %d wolves, want the cap of 2", held["Wolf"])
+	}
+	if held := r.Hold("#raffle1", map[string]int{"Wolf": 3, "Bolt": 1}, time.Hour); held["Wolf"] != 1 || held["Bolt"] != 1 {
+		t.Errorf("Hold held %v, want the last wolf and the bolt without a cap", held)
+	}
+
+	r.Release("#raffle1")
+	r.Offer([]int{3})
+	if held := r.Hold("bob", map[string]int{"Wolf": 1}, time.Hour); held["Wolf"] != 0 {
+		t.Errorf("Hold took a wolf that is offered in the running trade")
+	}
+	r.Offer(nil)
+
+	r.Hold("alice", map[string]int{"Bolt": 1}, time.Minute)
+	for id, hold := range r.holds {
+		if hold.Card == "Wolf" && hold.Expires.Before(time.Now().Add(time.Hour-time.Second)) {
+			t.Errorf("holding a bolt shortened the hold on wolf %d", id)
+		}
+	}
+
+	r.Refresh(testLibrary(LibraryCard{2, "Wolf", true, 0}, LibraryCard{4, "Bolt", true, 0}))
+	if held := r.HeldCards("alice"); held["Wolf"] != 1 || held["Bolt"] != 1 {
+		t.Errorf("after Refresh alice holds %v, want the wolf and bolt we still have", held)
+	}
+}
+
+func TestBotCardIdsNone(t *testing.T) {
+	useBotLibrary(t, testLibrary(LibraryCard{1, "Wolf", true, 0}))
+	if ids := botCardIds("Wolf", -2, make(map[int]bool)); ids != nil {
+		t.Errorf("botCardIds with a negative count = %v, want nil", ids)
+	}
+}
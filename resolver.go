@@ -0,0 +1,113 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+const ambiguityMargin = 0.05
+
+type Candidate struct {
+	Name       string
+	Confidence float64
+}
+
+type AliasStore struct {
+	sync.Mutex
+	filename string
+	aliases  map[string]string
+}
+
+var Aliases *AliasStore
+
+func LoadAliases(filename string) *AliasStore {
+	a := &AliasStore{filename: filename, aliases: make(map[string]string)}
+	loadJSON(filename, &a.aliases)
+	return a
+}
+
+func (a *AliasStore) Get(alias string) (card string, ok bool) {
+	a.Lock()
+	defer a.Unlock()
+	card, ok = a.aliases[strings.ToLower(alias)]
+	return
+}
+
+func (a *AliasStore) Set(alias, card string) {
+	a.Lock()
+	defer a.Unlock()
+	if card == "" {
+		delete(a.aliases, strings.ToLower(alias))
+	} else {
+		a.aliases[strings.ToLower(alias)] = card
+	}
+	saveJSON(a.filename, a.aliases)
+}
+
+// ResolveCard ranks all card types that could be meant by input. Aliases and exact names are certain,
+// typos within a Damerau-Levenshtein distance of 2 come next, followed by matches of single words and substrings.
+// Candidates with equal confidence are ordered by name.
+func ResolveCard(input string) []Candidate {
+	input = strings.ToLower(strings.TrimSpace(input))
+	if input == "" {
+		return nil
+	}
+
+	best := make(map[string]float64)
+	consider := func(name string, confidence float64) {
+		if confidence > best[name] {
+			best[name] = confidence
+		}
+	}
+
+	idx := Index()
+	if card, ok := Aliases.Get(input); ok {
+		return []Candidate{{card, 1}}
+	}
+	if card, ok := idx.Exact(input); ok {
+		return []Candidate{{card, 1}}
+	}
+
+	idx.Fuzzy(input, 2, func(name string, dist int) {
+		consider(name, 0.9-0.1*float64(dist))
+	})
+	for _, name := range idx.WithWord(input) {
+		consider(name, 0.6)
+	}
+	idx.Containing(input, func(name string, length int) {
+		consider(name, 0.5*float64(len(input))/float64(length))
+	})
+
+	candidates := make([]Candidate, 0, len(best))
+	for name, confidence := range best {
+		candidates = append(candidates, Candidate{name, confidence})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Confidence != candidates[j].Confidence {
+			return candidates[i].Confidence > candidates[j].Confidence
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+	return candidates
+}
+
+// resolveCardName returns the card meant by input, or up to three options if the best candidates are
+// too close to call. name is empty if nothing matched or the input is ambiguous.
+func resolveCardName(input string) (name string, options []string) {
+	candidates := ResolveCard(input)
+	if len(candidates) == 0 {
+		return "", nil
+	}
+	top := candidates[0]
+	if top.Confidence < 1 && len(candidates) > 1 && candidates[1].Confidence >= top.Confidence-ambiguityMargin {
+		for _, c := range candidates {
+			if len(options) == 3 || c.Confidence < top.Confidence-ambiguityMargin {
+				break
+			}
+			options = append(options, c.Name)
+		}
+		return "", options
+	}
+	return top.Name, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// useCardTypes replaces the card types with names and rebuilds the index, as if CardTypes had arrived.
+func useCardTypes(t testing.TB, names ...string) {
+	CardTypes = make(map[CardId]string)
+	for i, name := range names {
+		CardTypes[CardId(i+1)] = name
+	}
+	RebuildCardIndex()
+	Aliases = LoadAliases(filepath.Join(t.TempDir(), "aliases.json"))
+}
+
+var resolverCards = []string{
+	"Wolf", "Wolf Rider", "Werewolf", "Bear", "Bear Trap",
+	"Fire Bolt", "Fire Ball", "Fire Wall", "Fire Storm",
+}
+
+func TestResolveCardRanking(t *testing.T) {
+	useCardTypes(t, resolverCards...)
+
+	for _, test := range []struct {
+		input string
+		want  []string
+	}{
+		{"Wolf", []string{"Wolf"}},
+		{"  wolf rider ", []string{"Wolf Rider"}},
+		{"wolv", []string{"Wolf"}},
+		{"wlof", []string{"Wolf"}},
+		{"fire", []string{"Fire Ball", "Fire Bolt", "Fire Storm", "Fire Wall"}},
+		{"bear tr", []string{"Bear Trap"}},
+		{"", nil},
+	} {
+		var got []string
+		for _, c := range ResolveCard(test.input) {
+			got = append(got, c.Name)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ResolveCard(%q) = %v, want %v", test.input, got, test.want)
+		}
+	}
+
+	candidates := ResolveCard("wolf rder")
+	if len(candidates) == 0 || candidates[0].Name != "Wolf Rider" || candidates[0].Confidence != 0.8 {
+		t.Errorf("ResolveCard(\"wolf rder\") = %v, want Wolf Rider at 0.8 first", candidates)
+	}
+	for i := 1; i < len(candidates); i++ {
+		if candidates[i].Confidence > candidates[i-1].Confidence {
+			t.Errorf("ResolveCard(\"wolf rder\") is not ranked by confidence: %v", candidates)
+		}
+	}
+}
+
+func TestResolveCardAlias(t *testing.T) {
+	useCardTypes(t, resolverCards...)
+	Aliases.Set("WR", "Wolf Rider")
+
+	if got := ResolveCard("wr"); !reflect.DeepEqual(got, []Candidate{{"Wolf Rider", 1}}) {
+		t.Errorf("ResolveCard(\"wr\") = %v, want the aliased card", got)
+	}
+}
+
+func TestResolveCardName(t *testing.T) {
+	useCardTypes(t, resolverCards...)
+
+	for _, test := range []struct {
+		input   string
+		name    string
+		options []string
+	}{
+		{"werewolf", "Werewolf", nil},
+		{"werwolf", "Werewolf", nil},
+		{"fire blot", "Fire Bolt", nil},
+		{"fire", "", []string{"Fire Ball", "Fire Bolt", "Fire Storm"}},
+		{"dragon", "", nil},
+	} {
+		name, options := resolveCardName(test.input)
+		if name != test.name || !reflect.DeepEqual(options, test.options) {
+			t.Errorf("resolveCardName(%q) = %q, %v, want %q, %v", test.input, name, options, test.name, test.options)
+		}
+	}
+}
+
+func TestResolveCardNameAmbiguityMargin(t *testing.T) {
+	// "bat" is one edit away from both, so they tie; "bath" is an exact name and wins outright.
+	useCardTypes(t, "Bats", "Bath", "Cat")
+
+	if name, options := resolveCardName("bat"); name != "" || !reflect.DeepEqual(options, []string{"Bath", "Bats", "Cat"}) {
+		t.Errorf("resolveCardName(\"bat\") = %q, %v, want all three as options", name, options)
+	}
+	if name, options := resolveCardName("bath"); name != "Bath" || options != nil {
+		t.Errorf("resolveCardName(\"bath\") = %q, %v, want Bath", name, options)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+type RoomProfile struct {
+	Name     Channel
+	Commands []string
+	Announce bool
+	Language string
+}
+
+func RoomProfileFor(room Channel) (profile RoomProfile, ok bool) {
+	for _, profile := range Conf.Rooms {
+		if profile.Name == room {
+			return profile, true
+		}
+	}
+	return RoomProfile{}, false
+}
+
+// Allows tells whether the command may be used in this room. An empty command list allows everything.
+func (p RoomProfile) Allows(command string) bool {
+	if len(p.Commands) == 0 || command == "" {
+		return true
+	}
+	name := commandName(command)
+	for _, allowed := range p.Commands {
+		if allowed == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *State) JoinRooms() {
+	for _, profile := range Conf.Rooms {
+		s.JoinRoom(profile.Name)
+	}
+}
+
+func (s *State) Announce(key string, vars interface{}) {
+	s.announceEach(func(lang string) string {
+		return Tr(lang, key, vars)
+	})
+}
+
+func (s *State) announceEach(text func(lang string) string) {
+	for _, profile := range Conf.Rooms {
+		if profile.Announce {
+			lang := profile.Language
+			if lang == "" {
+				lang = "en"
+			}
+			s.Say(profile.Name, text(lang))
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+// Settlement says who pays how much gold to balance a trade.
+type Settlement struct {
+	Their     int // value of their cards to us
+	Mine      int // value of our cards
+	TheirGold int
+	MyGold    int // gold we should offer
+	TheyOwe   int // gold they still need to add
+	Refund    int // gold they put in beyond what they owe, which we pay back
+	Overpaid  int // gold they should take out again because we can't pay it back
+
+	TooExpensive bool
+	Budget       int
+}
+
+// Settle balances the trade so that our gold minus theirs equals the value of their cards minus ours.
+// Gold they put in beyond what they owe is paid back as long as the budget allows.
+func Settle(ts TradeStatus, budget int) Settlement {
+	st := Settlement{Their: ts.Their.Value, Mine: ts.My.Value, TheirGold: ts.Their.Gold, Budget: budget}
+	net := ts.Their.Value - ts.My.Value
+
+	if net > budget {
+		st.TooExpensive = true
+		return st
+	}
+	if net < 0 && ts.Their.Gold < -net {
+		st.TheyOwe = -net - ts.Their.Gold
+		return st
+	}
+	st.MyGold = net + ts.Their.Gold
+	if net < 0 {
+		st.Refund = st.MyGold
+	}
+	if st.MyGold > budget {
+		st.Refund = 0
+		st.Overpaid = st.MyGold - budget
+		st.MyGold = 0
+	}
+	return st
+}
@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+func TestSettle(t *testing.T) {
+	const budget = 1000
+	for _, test := range []struct {
+		name                   string
+		their, mine, theirGold int
+		want                   Settlement
+	}{
+		{"we buy", 500, 0, 0, Settlement{MyGold: 500}},
+		{"we buy up to the budget", 1000, 0, 0, Settlement{MyGold: 1000}},
+		{"we can't afford it", 1001, 0, 0, Settlement{TooExpensive: true}},
+		{"swap in our favor", 700, 400, 0, Settlement{MyGold: 300}},
+		{"they buy without gold", 0, 300, 0, Settlement{TheyOwe: 300}},
+		{"they buy with too little gold", 0, 300, 200, Settlement{TheyOwe: 100}},
+		{"they buy with exact gold", 0, 300, 300, Settlement{}},
+		{"they buy with too much gold", 0, 300, 500, Settlement{MyGold: 200, Refund: 200}},
+		{"they overpay beyond our budget", 0, 100, 2000, Settlement{Overpaid: 900}},
+		{"they sell and add gold", 800, 0, 500, Settlement{Overpaid: 300}},
+	} {
+		var ts TradeStatus
+		ts.Their.Value, ts.My.Value, ts.Their.Gold = test.their, test.mine, test.theirGold
+		want := test.want
+		want.Their, want.Mine, want.TheirGold, want.Budget = test.their, test.mine, test.theirGold, budget
+		if got := Settle(ts, budget); got != want {
+			t.Errorf("%s: Settle = %+v, want %+v", test.name, got, want)
+		}
+	}
+}
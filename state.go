@@ -29,11 +29,10 @@ type Message struct {
 }
 
 var (
-	CardTypes    = make(map[CardId]string)
-	CardRarities = make(map[string]int)
-	Libraries    = make(map[Player]MLibraryView)
-	Stocks       = make(map[Player]map[string]int)
-	PlayerIds    = make(map[Player]string)
+	CardTypes = make(map[CardId]string)
+	Libraries = make(map[Player]MLibraryView)
+	Stocks    = make(map[Player]map[string]int)
+	PlayerIds = make(map[Player]string)
 )
 
 func InitState(con net.Conn) *State {
@@ -114,6 +113,7 @@ func (s *State) JoinRoom(room Channel) {
 
 func (s *State) LeaveRoom(room Channel) {
 	s.SendRequest(Request{"msg": "RoomExit", "roomName": room})
+	Presence.LeaveRoom(room)
 }
 
 func (s *State) Say(room Channel, text string) {
@@ -149,7 +149,7 @@ func (s *State) Whisper(player Player, text string) {
 
 func (s *State) HandleReply(reply []byte) bool {
 	if len(reply) < 2 {
-		log.Println("reply is too short\n")
+		log.Println("reply is too short")
 		return false
 	}
 
@@ -187,11 +187,14 @@ func (s *State) HandleReply(reply []byte) bool {
 	case "CardTypes":
 		var v MCardTypes
 		json.Unmarshal(reply, &v)
+		Cards.Load(v)
 		for _, cardType := range v.CardTypes {
 			CardTypes[CardId(cardType.Id)] = cardType.Name
-			CardRarities[cardType.Name] = cardType.Rarity
 		}
+		RebuildCardIndex()
 		LoadPrices()
+		s.CheckNotifications()
+		s.CheckOrders()
 
 	case "Fail":
 		var v MFail
@@ -215,6 +218,7 @@ func (s *State) HandleReply(reply []byte) bool {
 	case "FriendUpdate":
 		var v MFriendUpdate
 		json.Unmarshal(reply, &v)
+		Presence.SetFriend(Player(v.Friend.Profile.Name), v.Friend.OnlineStatus)
 
 	case "GetBlockedPersons":
 		var v MGetBlockedPersons
@@ -235,6 +239,7 @@ func (s *State) HandleReply(reply []byte) bool {
 
 		for _, friend := range v.Friends {
 			PlayerIds[Player(friend.Profile.Name)] = friend.Profile.Id
+			Presence.SetFriend(Player(friend.Profile.Name), friend.OnlineState)
 		}
 
 	case "LibraryView":
@@ -262,6 +267,8 @@ func (s *State) HandleReply(reply []byte) bool {
 			}
 		}
 		Stocks[player] = stock
+		IndexLibrary(player, v)
+		libraryArrived(player)
 
 	case "Ok":
 		var v MOk
@@ -296,9 +303,17 @@ func (s *State) HandleReply(reply []byte) bool {
 	case "RoomInfo":
 		var v MRoomInfo
 		json.Unmarshal(reply, &v)
+		joined := make([]Player, 0, len(v.Updated))
 		for _, player := range v.Updated {
 			PlayerIds[Player(player.Name)] = player.Id
+			joined = append(joined, Player(player.Name))
 		}
+		left := make([]Player, 0, len(v.Removed))
+		for _, player := range v.Removed {
+			left = append(left, Player(player.Name))
+		}
+		Presence.UpdateRoom(Channel(v.RoomName), v.Reset, joined, left)
+
 	case "ServerInfo":
 		var v MServerInfo
 		json.Unmarshal(reply, &v)
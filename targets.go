@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// InventoryTarget says how many copies of a card we want. Target replaces the default of 1.5 copies
+// that prices are centered on. Below Min we buy at no less than the base value, from Max copies on we
+// only pay the floor and we never buy beyond Cap. Zero Min, Max or Cap means no bound.
+type InventoryTarget struct {
+	Card   string
+	Rarity string
+
+	Target float64
+	Min    int
+	Max    int
+	Cap    int
+}
+
+var defaultTarget = InventoryTarget{Target: 1.5}
+
+// TargetFor returns the target for the card itself or, failing that, for its rarity.
+func TargetFor(cardName string) InventoryTarget {
+	rarity := ""
+	if card, ok := Cards.ByName(cardName); ok {
+		rarity = card.RarityName()
+	}
+	target, found := defaultTarget, false
+	for _, t := range Conf.Targets {
+		if t.Card == cardName {
+			target, found = t, true
+			break
+		}
+		if !found && t.Card == "" && t.Rarity != "" && t.Rarity == rarity {
+			target, found = t, true
+		}
+	}
+	if target.Target == 0 {
+		target.Target = defaultTarget.Target
+	}
+	return target
+}
+
+// BuyLimit returns how many more copies of the card we'd buy, or -1 if there's no cap.
+func BuyLimit(card string) int {
+	t := TargetFor(card)
+	if t.Cap == 0 {
+		return -1
+	}
+	if limit := t.Cap - Stocks[Bot][card]; limit > 0 {
+		return limit
+	}
+	return 0
+}
+
+// overCap lists the cards of which we're offered more than we'd buy.
+func overCap(cards map[string]int) []string {
+	over := make([]string, 0)
+	for _, card := range sortedNames(cards) {
+		if limit := BuyLimit(card); limit >= 0 && cards[card] > limit {
+			over = append(over, card)
+		}
+	}
+	return over
+}
+
+type targetReport struct {
+	Below []string
+	Above []string
+	AtCap []string
+	Count int
+}
+
+// TargetReport lists the cards below their minimum (or target, if there's no minimum), above their
+// maximum and at their cap, with at most max names per list. Count includes the cards left out.
+func TargetReport(max int) targetReport {
+	report := targetReport{}
+	add := func(list *[]string, card string, stocked int, of interface{}) {
+		report.Count++
+		if len(*list) < max {
+			*list = append(*list, fmt.Sprintf("%s %d/%v", card, stocked, of))
+		}
+	}
+
+	names := make([]string, 0, len(CardTypes))
+	for _, card := range CardTypes {
+		names = append(names, card)
+	}
+	sort.Strings(names)
+	for _, card := range names {
+		t := TargetFor(card)
+		stocked := Stocks[Bot][card]
+		switch {
+		case t.Cap > 0 && stocked >= t.Cap:
+			add(&report.AtCap, card, stocked, t.Cap)
+		case t.Max > 0 && stocked >= t.Max:
+			add(&report.Above, card, stocked, t.Max)
+		case t.Min > 0 && stocked < t.Min:
+			add(&report.Below, card, stocked, t.Min)
+		case t.Min == 0 && float64(stocked) < t.Target:
+			add(&report.Below, card, stocked, t.Target)
+		}
+	}
+	return report
+}
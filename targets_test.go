@@ -0,0 +1,65 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTargetFor(t *testing.T) {
+	useCards(wolf, dragon, bolt)
+	defer func(targets []InventoryTarget) { Conf.Targets = targets }(Conf.Targets)
+	Conf.Targets = []InventoryTarget{
+		{Rarity: "rare", Target: 1, Cap: 3},
+		{Card: "Bolt", Min: 2, Max: 5},
+		{Rarity: "rare", Target: 4},
+	}
+
+	for _, test := range []struct {
+		card string
+		want InventoryTarget
+	}{
+		{"Wolf", InventoryTarget{Target: 1.5}},
+		{"Dragon", InventoryTarget{Rarity: "rare", Target: 1, Cap: 3}},
+		{"Bolt", InventoryTarget{Card: "Bolt", Target: 1.5, Min: 2, Max: 5}},
+		{"Unknown", InventoryTarget{Target: 1.5}},
+	} {
+		if got := TargetFor(test.card); got != test.want {
+			t.Errorf("TargetFor(%s) = %+v, want %+v", test.card, got, test.want)
+		}
+	}
+}
+
+func TestBuyLimit(t *testing.T) {
+	useCards(wolf, dragon, bolt)
+	defer func(targets []InventoryTarget, stock map[string]int) {
+		Conf.Targets, Stocks[Bot] = targets, stock
+	}(Conf.Targets, Stocks[Bot])
+	Conf.Targets = []InventoryTarget{{Card: "Dragon", Cap: 3}, {Card: "Bolt", Cap: 2}}
+	Stocks[Bot] = map[string]int{"Wolf": 10, "Dragon": 1, "Bolt": 4}
+
+	for card, want := range map[string]int{"Wolf": -1, "Dragon": 2, "Bolt": 0} {
+		if got := BuyLimit(card); got != want {
+			t.Errorf("BuyLimit(%s) = %d, want %d", card, got, want)
+		}
+	}
+	if got := overCap(map[string]int{"Wolf": 5, "Dragon": 3, "Bolt": 1}); !reflect.DeepEqual(got, []string{"Bolt", "Dragon"}) {
+		t.Errorf("overCap = %v, want [Bolt Dragon]", got)
+	}
+}
+
+func TestTargetReportCount(t *testing.T) {
+	useCardTypes(t, "Wolf", "Dragon", "Bolt", "Bear")
+	useCards(wolf, dragon, bolt)
+	defer func(targets []InventoryTarget, stock map[string]int) {
+		Conf.Targets, Stocks[Bot] = targets, stock
+	}(Conf.Targets, Stocks[Bot])
+	Conf.Targets = []InventoryTarget{{Card: "Dragon", Cap: 3}, {Card: "Bolt", Max: 2}}
+	Stocks[Bot] = map[string]int{"Wolf": 1, "Dragon": 3, "Bolt": 2, "Bear": 0}
+
+	// Wolf is below target too but left out of the list, which holds one name.
+	report := TargetReport(1)
+	want := targetReport{Below: []string{"Bear 0/1.5"}, Above: []string{"Bolt 2/2"}, AtCap: []string{"Dragon 3/3"}, Count: 4}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("TargetReport = %+v, want %+v", report, want)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"math"
 	"net/http"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -20,17 +21,21 @@ var Gold int
 var TradeRoom Channel
 
 type TradeStatus struct {
-	Partner Player
-	Updated bool
-	Their   struct {
+	Partner  Player
+	Updated  bool
+	TimedOut bool
+	Swap     bool
+	Their    struct {
 		Value    int
 		Cards    map[string]int
+		CardIds  []int
 		Gold     int
 		Accepted bool
 	}
 	My struct {
 		Value    int
 		Cards    map[string]int
+		CardIds  []int
 		Gold     int
 		Accepted bool
 	}
@@ -44,16 +49,9 @@ func LoadPrices() {
 	lowerPrices := make(map[string]int)
 	upperPrices := make(map[string]int)
 	for _, card := range CardTypes {
-		switch CardRarities[card] {
-		case 0:
-			lowerPrices[card] = 50
-			upperPrices[card] = 150
-		case 1:
-			lowerPrices[card] = 300
-			upperPrices[card] = 600
-		case 2:
-			lowerPrices[card] = 600
-			upperPrices[card] = 1500
+		if rule, ok := PriceRuleFor(card); ok {
+			lowerPrices[card] = rule.Lower
+			upperPrices[card] = rule.Upper
 		}
 		Prices[card] = (lowerPrices[card] + upperPrices[card]) / 2
 	}
@@ -91,13 +89,8 @@ func LoadPrices() {
 }
 
 func MinimumValue(card string) int {
-	switch CardRarities[card] {
-	case 0:
-		return 25
-	case 1:
-		return 50
-	case 2:
-		return 100
+	if rule, ok := PriceRuleFor(card); ok {
+		return rule.Floor
 	}
 	return -1
 }
@@ -115,27 +108,19 @@ func BaseValue(card string) int {
 	}
 
 	newPrice := 9999
-
-	switch CardRarities[card] {
-	case 0:
-		newPrice = 100
-	case 1:
-		newPrice = 600
-	case 2:
-		newPrice = 1200
+	if rule, ok := PriceRuleFor(card); ok && rule.Base != 0 {
+		newPrice = rule.Base
 	}
 
 	return int(float64(Prices[card])*(1-f) + float64(newPrice)*f)
 }
 
 func (s *State) DeterminePrice(card string, num int, buy bool) int {
-	const (
-		N = 1.5
-		K = 10
-	)
+	const K = 10
+	target := TargetFor(card)
 	expify := func(card string, stocked int) float64 {
 		basePrice := float64(BaseValue(card))
-		n := float64(stocked) - N
+		n := float64(stocked) - target.Target
 		p := math.Exp(-n * n / K)
 		if n >= 0 {
 			return basePrice * p
@@ -150,7 +135,13 @@ func (s *State) DeterminePrice(card string, num int, buy bool) int {
 	for i := 0; i < num; i++ {
 		if buy {
 			goldFactor := math.Min(float64(Gold), 10000.0)/20000.0 + 0.5
-			price += int(math.Max(float64(MinimumValue(card)), expify(card, stocked)*goldFactor))
+			p := math.Max(float64(MinimumValue(card)), expify(card, stocked)*goldFactor)
+			if target.Max > 0 && stocked >= target.Max {
+				p = float64(MinimumValue(card))
+			} else if stocked < target.Min {
+				p = math.Max(p, float64(BaseValue(card)))
+			}
+			price += int(p)
 			stocked++
 
 		} else {
@@ -178,33 +169,53 @@ func (s *State) ParseTradeView(v MTradeView) {
 		tradePartner = Player(v.From.Profile.Name)
 	}
 
-	convertAndCount := func(cardIds []int, player Player) map[string]int {
-		count := make(map[string]int)
-		for _, id := range cardIds {
-			for _, card := range Libraries[player].Cards {
-				if card.Id == id {
-					cardName := CardTypes[CardId(card.TypeId)]
-					count[cardName] = count[cardName] + 1
-					break
-				}
-			}
-		}
-		return count
-	}
-
 	ts := TradeStatus{}
 	ts.Updated = v.Modified
 	ts.Partner = tradePartner
 	ts.Their.Accepted = their.Accepted
-	ts.Their.Cards = convertAndCount(their.CardIds, tradePartner)
+	ts.Their.Cards = LibraryOf(tradePartner).Count(their.CardIds)
+	ts.Their.CardIds = their.CardIds
 	ts.Their.Gold = their.Gold
 	ts.My.Accepted = my.Accepted
-	ts.My.Cards = convertAndCount(my.CardIds, Bot)
+	ts.My.Cards = LibraryOf(Bot).Count(my.CardIds)
+	ts.My.CardIds = my.CardIds
 	ts.My.Gold = my.Gold
 
 	s.chTradeStatus <- ts
 }
 
+// offerExclusions lists the cards we must not offer to the player: the ones already offered, the
+// protected ones and the ones reserved for someone else, including auctions and raffles.
+func offerExclusions(player Player, offered []int) map[int]bool {
+	exclude := Reservations.HeldByOthers(player)
+	for _, id := range offered {
+		exclude[id] = true
+	}
+	for id := range LibraryOf(Bot).Protected() {
+		exclude[id] = true
+	}
+	return exclude
+}
+
+// botCardIds picks up to num of our tradable copies of the card in offer order, leaving out the
+// excluded ids and adding the picked ones to them.
+func botCardIds(cardName string, num int, exclude map[int]bool) []int {
+	if num <= 0 {
+		return nil
+	}
+	cardIds := make([]int, 0, num)
+	for _, id := range LibraryOf(Bot).Tradable(cardName) {
+		if len(cardIds) >= num {
+			break
+		}
+		if !exclude[id] {
+			cardIds = append(cardIds, id)
+			exclude[id] = true
+		}
+	}
+	return cardIds
+}
+
 func (s *State) InitiateTrade(player Player, timeout time.Duration) chan TradeStatus {
 	s.SendRequest(Request{"msg": "TradeInvite", "profile": PlayerIds[player]})
 	accepted := false
@@ -240,15 +251,63 @@ func (s *State) InitiateTrade(player Player, timeout time.Duration) chan TradeSt
 	return s.chTradeStatus
 }
 
+// swapAcceptable reports whether a card-for-card trade is in our favor or within Conf.SwapTolerance
+// percent of it.
+func swapAcceptable(ts TradeStatus) bool {
+	if len(ts.Their.Cards) == 0 || len(ts.My.Cards) == 0 {
+		return false
+	}
+	return ts.Their.Value*100 >= ts.My.Value*(100-Conf.SwapTolerance)
+}
+
+type SummaryLine struct {
+	Card  string
+	Num   int
+	Total int
+}
+
+func summaryLines(cards map[string]int, value func(card string, num int) int) []SummaryLine {
+	lines := make([]SummaryLine, 0, len(cards))
+	for _, card := range sortedNames(cards) {
+		num := cards[card]
+		lines = append(lines, SummaryLine{card, num, value(card, num)})
+	}
+	return lines
+}
+
+// tradeFingerprint identifies exactly what's on the table, so we notice any change after the summary.
+func tradeFingerprint(ts TradeStatus) string {
+	their := append([]int(nil), ts.Their.CardIds...)
+	my := append([]int(nil), ts.My.CardIds...)
+	sort.Ints(their)
+	sort.Ints(my)
+	return fmt.Sprint(their, ts.Their.Gold, my, ts.My.Gold)
+}
+
+func tradeValues(cards map[string]int, value func(card string, num int) int) []CardPrice {
+	values := make([]CardPrice, 0, len(cards))
+	for _, card := range sortedNames(cards) {
+		name := card
+		if num := cards[card]; num > 1 {
+			name = fmt.Sprintf("%dx %s", num, card)
+		}
+		values = append(values, CardPrice{name, value(card, cards[card])})
+	}
+	return values
+}
+
 func (s *State) Trade(tradePartner Player) (ts TradeStatus) {
 	// Send them a trade invite and see if they accept
 	chTradeStatus := s.InitiateTrade(tradePartner, 40*time.Second)
 	if chTradeStatus != nil {
 		defer s.LeaveRoom(TradeRoom)
+		defer Reservations.Offer(nil)
+		s.RequestLibrary(tradePartner)
 		lastActivity := time.Now()
 		startTime := time.Now()
 
 		donation := false
+		swap := false
 
 		minuteWarning := false
 		tenSecondWarning := false
@@ -256,25 +315,44 @@ func (s *State) Trade(tradePartner Player) (ts TradeStatus) {
 
 		cardsChanged := false
 
-		s.Say(TradeRoom, fmt.Sprintf("Welcome %s. This is an automated trading unit. If you don't know what to do, just say '!help'.", tradePartner))
+		summary := ""
+		summaryTime := time.Time{}
+		confirmed := false
+		var explained Settlement
 
-		request := WTBrequests[tradePartner]
-		if len(request) > 0 {
-			cardIds := make([]int, 0)
+		say := func(key string, vars interface{}) {
+			s.Say(TradeRoom, Tr(LanguageFor(tradePartner, TradeRoom), key, vars))
+		}
 
-			for cardName, num := range request {
-				for _, card := range Libraries[Bot].Cards {
-					if card.Tradable && CardTypes[CardId(card.TypeId)] == cardName {
-						cardIds = append(cardIds, card.Id)
-						num--
-						if num <= 0 {
-							break
-						}
-					}
-				}
+		say("trade.welcome", Vars{"Partner": tradePartner})
+
+		var offer Counteroffer
+		hasOffer := false
+		suggest := func() {
+			offer, hasOffer = s.Counteroffer(tradePartner, ts)
+			if !hasOffer {
+				say("trade.no_suggestion", nil)
+				return
 			}
+			say("trade.suggest", Vars{
+				"Remove":   cardCounts(offer.Remove),
+				"KeepGold": offer.KeepGold,
+				"KeepOwed": offer.KeepOwed,
+				"Add":      cardCounts(offer.Add),
+				"AddGold":  offer.AddGold,
+			})
+		}
+
+		cardIds := Reservations.Held(tradePartner)
+		held := Reservations.HeldCards(tradePartner)
+		exclude := offerExclusions(tradePartner, cardIds)
+		request := WTBRequest(tradePartner)
+		for _, cardName := range sortedNames(request) {
+			cardIds = append(cardIds, botCardIds(cardName, max(request[cardName]-held[cardName], 0), exclude)...)
+		}
+		if len(cardIds) > 0 {
 			s.SendRequest(Request{"msg": "TradeAddCards", "cardIds": cardIds})
-			s.Say(TradeRoom, "I've initialized the trade room with your last WTB request. You can !reset to undo this.")
+			say("trade.wtb_init", nil)
 		}
 
 		messages := s.Listen()
@@ -291,30 +369,31 @@ func (s *State) Trade(tradePartner Player) (ts TradeStatus) {
 					command := strings.ToLower(m.Text)
 
 					if command == "!help" {
-						s.Say(TradeRoom, "Just add the scrolls you want to sell on your side. To buy scrolls from me, say 'wtb [list of scrolls]'"+
-							" and I'll add everything I have on that list. You can also !add or !remove single cards."+
-							" Not sure about the gold? Just ask for the !price and I'll list it up.")
+						say("trade.help", nil)
+
+					} else if command == "!swap" {
+						swap = !swap
+						if swap {
+							say("trade.swap_on", Vars{"Tolerance": Conf.SwapTolerance})
+							if ts.My.Gold != 0 {
+								s.SendRequest(Request{"msg": "TradeSetGold", "gold": 0})
+							}
+							cardsChanged = true
+						} else {
+							say("trade.swap_off", nil)
+						}
 
 					} else if command == "!donation" {
 						donation = !donation
 						if donation {
-							s.Say(TradeRoom, "I will consider everything you put into this trade as a donation. Much appreciated!"+
-								" If you change your mind, just repeat the command.")
+							say("trade.donation_on", nil)
 						} else {
-							s.Say(TradeRoom, "Okay :(")
+							say("trade.donation_off", nil)
 						}
 
 					} else if command == "!reset" {
-						for cardName, num := range ts.My.Cards {
-							for _, card := range Libraries[Bot].Cards {
-								if CardTypes[CardId(card.TypeId)] == cardName && card.Tradable {
-									s.SendRequest(Request{"msg": "TradeRemoveCard", "cardId": card.Id})
-									num--
-									if num <= 0 {
-										break
-									}
-								}
-							}
+						for _, id := range ts.My.CardIds {
+							s.SendRequest(Request{"msg": "TradeRemoveCard", "cardId": id})
 						}
 
 					} else if command == "!price" {
@@ -331,11 +410,11 @@ func (s *State) Trade(tradePartner Player) (ts TradeStatus) {
 						}
 						myValue := make(map[string]int)
 						for card, num := range ts.My.Cards {
-							myValue[format(card, num)] = s.DeterminePrice(card, num, false)
+							myValue[format(card, num)] = s.SellPrice(tradePartner, card, num)
 						}
 
-						list := func(value map[string]int) []string {
-							lines := make([]string, len(value))
+						list := func(value map[string]int) []CardPrice {
+							lines := make([]CardPrice, len(value))
 							for i, _ := range lines {
 								mostGold := 0
 								nextCard := ""
@@ -346,25 +425,23 @@ func (s *State) Trade(tradePartner Player) (ts TradeStatus) {
 										nextCard = card
 									}
 								}
-								lines[i] = fmt.Sprintf("%s for %dg", nextCard, mostGold)
+								lines[i] = CardPrice{nextCard, mostGold}
 								value[nextCard] = 0
 							}
 							return lines
 						}
-						msg := ""
-						if len(theirValue) > 0 {
-							msg += fmt.Sprintf("I'll buy %s. ", strings.Join(list(theirValue), ", "))
-						}
-						if len(myValue) > 0 {
-							msg += fmt.Sprintf("I'll sell %s. ", strings.Join(list(myValue), ", "))
-						}
 						diff := ts.Their.Value - ts.My.Value
+						owed := diff
 						if diff < 0 {
-							msg += fmt.Sprintf("Thus you owe me %dg.", -diff)
-						} else {
-							msg += fmt.Sprintf("Thus I owe you %dg.", diff)
+							owed = -diff
 						}
-						s.Say(m.Channel, msg)
+						say("trade.price", Vars{
+							"Buy":      list(theirValue),
+							"Sell":     list(myValue),
+							"TheyOwe":  diff < 0,
+							"Owed":     owed,
+							"Discount": s.CompletionDiscount(tradePartner, ts.My.Cards),
+						})
 
 					} else if strings.HasPrefix(command, "!add") || strings.HasPrefix(command, "!wtb") || strings.HasPrefix(command, "wtb") {
 						cardlist := strings.TrimPrefix(command, "!add")
@@ -373,68 +450,84 @@ func (s *State) Trade(tradePartner Player) (ts TradeStatus) {
 
 						cardIds := make([]int, 0)
 
-						requestedCards, failedWords := parseCardList(cardlist)
+						requestedCards, errors := parseCardList(cardlist, Reservations.Available(tradePartner))
 
-						WTBrequests[tradePartner] = requestedCards
-						if len(requestedCards) > 0 {
+						SetWTBRequest(tradePartner, requestedCards)
+						if len(requestedCards) > 0 || len(errors) > 0 {
+							exclude := offerExclusions(tradePartner, ts.My.CardIds)
 							missing := make(map[string]int)
 							for requestedCard, num := range requestedCards {
-								skip := ts.My.Cards[requestedCard]
-								for _, card := range Libraries[Bot].Cards {
-									if CardTypes[CardId(card.TypeId)] != requestedCard || !card.Tradable {
-										continue
-									}
-									skip--
-									if num > 0 && skip < 0 {
-										cardIds = append(cardIds, card.Id)
-										num--
-									}
-								}
-								if num > 0 {
-									missing[requestedCard] = num
+								added := botCardIds(requestedCard, num, exclude)
+								cardIds = append(cardIds, added...)
+								if num > len(added) {
+									missing[requestedCard] = num - len(added)
 								}
 							}
 
-							reply := ""
-							if len(missing) > 0 {
+							if len(missing) > 0 || len(errors) > 0 {
 								list := make([]string, 0, len(missing))
 								for card, num := range missing {
 									list = append(list, fmt.Sprintf("%dx %s", num, card))
 								}
-								reply = fmt.Sprintf("I don't have %s.", strings.Join(list, ", "))
-							}
-							if len(failedWords) > 0 {
-								reply += fmt.Sprintf("I don't know what '%s' is.", strings.Join(failedWords, ", "))
-							}
-							if reply != "" {
-								s.Say(TradeRoom, reply)
+								problems := parseProblems(LanguageFor(tradePartner, TradeRoom), errors)
+								say("trade.add_failed", Vars{"Missing": list, "Problems": problems})
 							}
 							if len(cardIds) > 0 {
 								s.SendRequest(Request{"msg": "TradeAddCards", "cardIds": cardIds})
 							}
 						}
 
+					} else if command == "!confirm" {
+						if summary == "" {
+							say("trade.nothing_to_confirm", nil)
+						} else if summary != tradeFingerprint(ts) {
+							say("trade.summary_changed", nil)
+						} else {
+							confirmed = true
+						}
+
+					} else if command == "!suggest" {
+						suggest()
+
+					} else if command == "!ok" {
+						if !hasOffer {
+							say("trade.no_suggestion", nil)
+						} else if len(offer.Add) == 0 {
+							say("trade.suggest_remove", Vars{"Remove": cardCounts(offer.Remove)})
+						} else {
+							exclude := offerExclusions(tradePartner, ts.My.CardIds)
+							cardIds := make([]int, 0)
+							for card, num := range offer.Add {
+								cardIds = append(cardIds, botCardIds(card, num, exclude)...)
+							}
+							s.SendRequest(Request{"msg": "TradeAddCards", "cardIds": cardIds})
+							hasOffer = false
+						}
+
 					} else if command == "!remove" {
-						s.Say(TradeRoom, "You have to name the card that I will remove.")
+						say("trade.remove_which", nil)
 
 					} else if strings.HasPrefix(command, "!remove") {
-						cardName := matchCardName(strings.TrimPrefix(command, "!remove "))
+						word := strings.TrimPrefix(command, "!remove ")
+						cardName, options := resolveCardName(word)
 						_, ok := Stocks[Bot][cardName]
 
 						alreadyOffered := ts.My.Cards[cardName]
 
-						if !ok {
-							s.Say(m.Channel, fmt.Sprintf("There is no scroll named '%s'.", cardName))
+						if len(options) > 0 {
+							say("card.ambiguous", ParseError{Word: word, Options: options})
+						} else if !ok {
+							if cardName == "" {
+								cardName = word
+							}
+							say("trade.no_such_card", Vars{"Card": cardName})
 						} else if alreadyOffered == 0 {
-							s.Say(m.Channel, fmt.Sprintf("%s is not part of this trade!", cardName))
+							say("trade.not_in_trade", Vars{"Card": cardName})
 						} else {
-							for _, card := range Libraries[Bot].Cards {
-								if card.Tradable && CardTypes[CardId(card.TypeId)] == cardName {
-									if alreadyOffered == 1 {
-										s.SendRequest(Request{"msg": "TradeRemoveCard", "cardId": card.Id})
-										break
-									}
-									alreadyOffered--
+							for _, id := range ts.My.CardIds {
+								if card, ok := LibraryOf(Bot).Card(id); ok && card.Name == cardName {
+									s.SendRequest(Request{"msg": "TradeRemoveCard", "cardId": id})
+									break
 								}
 							}
 						}
@@ -449,6 +542,7 @@ func (s *State) Trade(tradePartner Player) (ts TradeStatus) {
 				oldValueSum := ts.Their.Value + ts.My.Value
 
 				ts = newTradeStatus
+				Reservations.Offer(ts.My.CardIds)
 				// sanity check..
 				if ts.Partner != tradePartner {
 					s.Whisper("redefiance", fmt.Sprintf("I failed so hard >.> %s != %s", ts.Partner, tradePartner))
@@ -460,10 +554,11 @@ func (s *State) Trade(tradePartner Player) (ts TradeStatus) {
 				}
 
 				if ts.My.Accepted && ts.Their.Accepted {
-					s.Say(TradeRoom, "Thanks!")
+					say("trade.thanks", nil)
 					if donation {
 						if diff := ts.Their.Value + ts.Their.Gold - ts.My.Value - ts.My.Gold; diff > 0 {
-							s.Say("clockwork", fmt.Sprintf("%s just donated stuff worth %dg. Praise to them!", tradePartner, diff))
+							s.Announce("trade.donated", Vars{"Partner": tradePartner, "Value": diff})
+							Raffles.Donated(ts.Their.Cards, diff)
 						}
 					}
 
@@ -476,24 +571,37 @@ func (s *State) Trade(tradePartner Player) (ts TradeStatus) {
 						Stocks[Bot][card] -= num
 					}
 
-					alreadySold := make(map[string]bool)
 					cardIds := make([]int, 0)
+					held := offerExclusions("", ts.My.CardIds)
+					library := LibraryOf(Bot)
 
-					for _, card := range Libraries[Bot].Cards {
-						cardName := CardTypes[CardId(card.TypeId)]
-						if !alreadySold[cardName] && card.Tradable && s.DeterminePrice(cardName, 1, false) <= MinimumValue(cardName) {
-							alreadySold[cardName] = true
-							cardIds = append(cardIds, card.Id)
+					for _, cardName := range library.TradableTypes() {
+						if s.DeterminePrice(cardName, 1, false) > MinimumValue(cardName) {
+							continue
+						}
+						for _, id := range library.Tradable(cardName) {
+							if !held[id] {
+								cardIds = append(cardIds, id)
+								break
+							}
 						}
 					}
 					if len(cardIds) > 0 {
 						s.SendRequest(Request{"msg": "SellCards", "cardIds": cardIds})
-						for id := range cardIds {
-							name := CardTypes[CardId(id)]
-							Stocks[Bot][name] = Stocks[Bot][name] - 1
-							Gold += MinimumValue(name)
+						for _, id := range cardIds {
+							card, _ := library.Card(id)
+							Stocks[Bot][card.Name] = Stocks[Bot][card.Name] - 1
+							Gold += MinimumValue(card.Name)
 						}
 					}
+					// Our card ids changed hands, so the index must not offer them again.
+					s.RequestLibrary(Bot)
+					EndCompletion(tradePartner)
+					Reservations.Release(tradePartner)
+					Auctions.Settle(tradePartner, ts.My.Cards)
+					Orders.Settle(tradePartner, ts.My.Cards)
+					Raffles.Claim(tradePartner, ts.My.Cards)
+					ts.Swap = swap
 					logTrade(ts)
 					return
 				}
@@ -503,54 +611,80 @@ func (s *State) Trade(tradePartner Player) (ts TradeStatus) {
 				}
 
 				for card, num := range ts.My.Cards {
-					ts.My.Value += s.DeterminePrice(card, num, false)
+					ts.My.Value += s.SellPrice(tradePartner, card, num)
 				}
+				ts.My.Value -= s.CompletionDiscount(tradePartner, ts.My.Cards)
 
 				if oldValueSum != ts.Their.Value+ts.My.Value {
 					cardsChanged = true
+					hasOffer = false
 				}
 
-				goldNeeded := ts.Their.Value - ts.My.Value + ts.Their.Gold
+				goldNeeded := Settle(ts, GoldForTrade()).MyGold
+				if swap {
+					goldNeeded = 0
+				}
 				if goldNeeded != ts.My.Gold {
-					if goldNeeded > 0 && GoldForTrade() >= goldNeeded {
-						s.SendRequest(Request{"msg": "TradeSetGold", "gold": goldNeeded})
-					} else if ts.My.Gold != 0 {
-						s.SendRequest(Request{"msg": "TradeSetGold", "gold": 0})
-					}
+					s.SendRequest(Request{"msg": "TradeSetGold", "gold": goldNeeded})
 				}
 
 			case <-ticker:
 				if time.Now().After(lastActivity.Add(time.Minute)) && time.Now().After(lastIdleWarning.Add(time.Minute)) {
-					s.Say(TradeRoom, "You have been idle for a minute. This trade window will close in 30 seconds unless you interact with it.")
+					say("trade.idle", nil)
 					lastIdleWarning = time.Now()
 				}
 
 				if time.Now().After(lastActivity.Add(time.Minute + 30*time.Second)) {
-					s.Say(TradeRoom, "Time's up!")
+					say("trade.times_up", nil)
+					ts.TimedOut = true
 					return
 				}
 
 				if !minuteWarning && time.Now().After(startTime.Add(4*time.Minute)) {
-					s.Say(TradeRoom, "Please finish the trade within the next minute.")
+					say("trade.one_minute", nil)
 					minuteWarning = true
 				}
 				if !tenSecondWarning && time.Now().After(startTime.Add(4*time.Minute+50*time.Second)) {
-					s.Say(TradeRoom, "You have 10 seconds left to finish the trade.")
+					say("trade.ten_seconds", nil)
 					tenSecondWarning = true
 				}
 				if time.Now().After(startTime.Add(time.Minute * 5)) {
-					s.Say(TradeRoom, "Time's up!")
+					say("trade.times_up", nil)
+					ts.TimedOut = true
 					return
 				}
 
+				// Wait until our gold matches the settlement, so the echo of our own TradeSetGold doesn't
+				// make us explain it again.
+				settlement := Settle(ts, GoldForTrade())
+				settled := ts.My.Gold == settlement.MyGold
+				if settlement != explained && settled && !swap && !donation && time.Now().After(lastActivity.Add(time.Second*2)) {
+					explained = settlement
+					if settlement.TooExpensive {
+						say("trade.too_expensive", Vars{"Budget": GoldForTrade()})
+						suggest()
+					} else if len(ts.Their.Cards)+len(ts.My.Cards) > 0 {
+						say("trade.settlement", settlement)
+					}
+				}
+
+				refused := overCap(ts.Their.Cards)
 				if cardsChanged && time.Now().After(lastActivity.Add(time.Second*2)) {
 					cardsChanged = false
 
-					value := ts.Their.Value - ts.My.Value
-					if value > GoldForTrade() && !donation {
-						s.Say(TradeRoom, fmt.Sprintf("Sorry - I only have %d gold at my disposal. Please take something out. Or is this a !donation?", GoldForTrade()))
-					} else if value < 0 {
-						s.Say(TradeRoom, fmt.Sprintf("Please set your gold offer to %dg", -value))
+					if len(refused) > 0 && !donation {
+						say("trade.over_cap", Vars{"Cards": refused})
+					}
+
+					if swap {
+						say("trade.swap_values", Vars{
+							"Buy":       tradeValues(ts.Their.Cards, func(card string, num int) int { return s.DeterminePrice(card, num, true) }),
+							"Sell":      tradeValues(ts.My.Cards, func(card string, num int) int { return s.SellPrice(tradePartner, card, num) }),
+							"Their":     ts.Their.Value,
+							"Mine":      ts.My.Value,
+							"Fair":      swapAcceptable(ts),
+							"Tolerance": Conf.SwapTolerance,
+						})
 					}
 				}
 
@@ -558,17 +692,44 @@ func (s *State) Trade(tradePartner Player) (ts TradeStatus) {
 				theirGain := ts.My.Value + ts.My.Gold
 				canAccept := false
 
-				if myGain >= theirGain && myGain > 0 {
+				gift := len(Raffles.PrizeFor(tradePartner)) > 0 && len(ts.My.Cards) > 0
+
+				if swap {
+					canAccept = ts.Their.Gold == 0 && ts.My.Gold == 0 && swapAcceptable(ts)
+				} else if myGain >= theirGain && (myGain > 0 || gift) {
 					canAccept = true
 					if !donation {
 						canAccept = myGain == theirGain
 					}
 				}
 
+				if len(refused) > 0 && !donation {
+					canAccept = false
+				}
+
 				// s.Say(TradeRoom, fmt.Sprintf("%d %d %s %s", myGain, theirGain, canAccept, donation))
 
-				if canAccept && !ts.My.Accepted && time.Now().After(lastActivity.Add(time.Second*7)) {
-					s.SendRequest(Request{"msg": "TradeAcceptBargain"})
+				fingerprint := tradeFingerprint(ts)
+				if summary != "" && summary != fingerprint {
+					summary = ""
+					confirmed = false
+				}
+
+				if canAccept && !ts.My.Accepted {
+					timeout := time.Duration(Conf.ConfirmTimeoutSeconds) * time.Second
+					if summary == "" && time.Now().After(lastActivity.Add(time.Second*7)) {
+						summary = fingerprint
+						summaryTime = time.Now()
+						say("trade.summary", Vars{
+							"Buy":       summaryLines(ts.Their.Cards, func(card string, num int) int { return s.DeterminePrice(card, num, true) }),
+							"Sell":      summaryLines(ts.My.Cards, func(card string, num int) int { return s.SellPrice(tradePartner, card, num) }),
+							"TheirGold": ts.Their.Gold,
+							"MyGold":    ts.My.Gold,
+							"Seconds":   Conf.ConfirmTimeoutSeconds,
+						})
+					} else if summary != "" && (confirmed || (timeout > 0 && time.Now().After(summaryTime.Add(timeout)))) {
+						s.SendRequest(Request{"msg": "TradeAcceptBargain"})
+					}
 				}
 			}
 		}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func tradeStatus(their map[string]int, theirValue int, my map[string]int, myValue int) (ts TradeStatus) {
+	ts.Their.Cards, ts.Their.Value = their, theirValue
+	ts.My.Cards, ts.My.Value = my, myValue
+	return ts
+}
+
+func TestSwapAcceptable(t *testing.T) {
+	defer func(tolerance int) { Conf.SwapTolerance = tolerance }(Conf.SwapTolerance)
+	Conf.SwapTolerance = 5
+
+	wolf := map[string]int{"Wolf": 1}
+	bears := map[string]int{"Bear": 2}
+	for _, test := range []struct {
+		name string
+		ts   TradeStatus
+		want bool
+	}{
+		{"in our favor", tradeStatus(wolf, 1200, bears, 1000), true},
+		{"even", tradeStatus(wolf, 1000, bears, 1000), true},
+		{"at the tolerance", tradeStatus(wolf, 950, bears, 1000), true},
+		{"beyond the tolerance", tradeStatus(wolf, 949, bears, 1000), false},
+		{"nothing from them", tradeStatus(map[string]int{}, 0, bears, 1000), false},
+		{"nothing from us", tradeStatus(wolf, 1000, nil, 0), false},
+	} {
+		if got := swapAcceptable(test.ts); got != test.want {
+			t.Errorf("%s: swapAcceptable = %v, want %v", test.name, got, test.want)
+		}
+	}
+
+	Conf.SwapTolerance = 0
+	if swapAcceptable(tradeStatus(wolf, 999, bears, 1000)) {
+		t.Error("swapAcceptable allowed a loss without tolerance")
+	}
+}
+
+func TestSummaryLines(t *testing.T) {
+	prices := map[string][]int{"Wolf": {0, 150, 301, 451}, "Bear": {0, 99}}
+	value := func(card string, num int) int { return prices[card][num] }
+
+	got := summaryLines(map[string]int{"Wolf": 3, "Bear": 1}, value)
+	want := []SummaryLine{{"Bear", 1, 99}, {"Wolf", 3, 451}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("summaryLines = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import "sync"
+
+// wtbRequests holds the cards each player last asked for, which we add when their trade starts. It's
+// written from the main loop, the reply handler and the post-trade checks.
+var wtbRequests = struct {
+	sync.Mutex
+	players map[Player]map[string]int
+}{players: make(map[Player]map[string]int)}
+
+func SetWTBRequest(player Player, cards map[string]int) {
+	wtbRequests.Lock()
+	defer wtbRequests.Unlock()
+	request := make(map[string]int, len(cards))
+	for card, num := range cards {
+		request[card] = num
+	}
+	wtbRequests.players[player] = request
+}
+
+// AddWTBRequest makes sure the player's request asks for at least num copies of the card, keeping the rest of it.
+func AddWTBRequest(player Player, card string, num int) {
+	wtbRequests.Lock()
+	defer wtbRequests.Unlock()
+	request := wtbRequests.players[player]
+	if request == nil {
+		request = make(map[string]int)
+		wtbRequests.players[player] = request
+	}
+	if request[card] < num {
+		request[card] = num
+	}
+}
+
+func WTBRequest(player Player) map[string]int {
+	wtbRequests.Lock()
+	defer wtbRequests.Unlock()
+	request := make(map[string]int, len(wtbRequests.players[player]))
+	for card, num := range wtbRequests.players[player] {
+		request[card] = num
+	}
+	return request
+}